@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"io"
 	"log"
@@ -74,9 +75,7 @@ func handler(ctx context.Context) {
 			wg.Add(1)
 		}
 
-		EDINETCode := report.EdinetCode
 		companyName := report.FilerName
-		docID := report.DocId
 		var periodStart string
 		var periodEnd string
 		if report.PeriodStart == "" || report.PeriodEnd == "" {
@@ -103,6 +102,16 @@ func handler(ctx context.Context) {
 			periodEnd = report.PeriodEnd
 		}
 
+		// 投資信託の場合はファンドコードごとに登録する
+		if utils.IsFundReport(report) {
+			if utils.Parallel == "true" {
+				go utils.RegisterFundReport(report, periodStart, periodEnd, &wg)
+			} else {
+				utils.RegisterFundReport(report, periodStart, periodEnd, &wg)
+			}
+			continue
+		}
+
 		// ファンダメンタルズ
 		fundamental := utils.Fundamental{
 			CompanyName:     companyName,
@@ -115,10 +124,10 @@ func handler(ctx context.Context) {
 		}
 		if utils.Parallel == "true" {
 			// 並列で処理する場合
-			go utils.RegisterReport(utils.DynamoClient, EDINETCode, docID, report.DateKey, companyName, periodStart, periodEnd, &fundamental, &wg)
+			go utils.RegisterReport(utils.DynamoClient, report, periodStart, periodEnd, &fundamental, &wg)
 		} else {
 			// 直列で処理する場合
-			utils.RegisterReport(utils.DynamoClient, EDINETCode, docID, report.DateKey, companyName, periodStart, periodEnd, &fundamental, &wg)
+			utils.RegisterReport(utils.DynamoClient, report, periodStart, periodEnd, &fundamental, &wg)
 		}
 	}
 	// 並列で処理する場合
@@ -133,6 +142,15 @@ func handler(ctx context.Context) {
 
 func main() {
 	fmt.Println("main start")
+	// --dry-run を指定した場合 S3, DynamoDB には書き込まない
+	dryRun := flag.Bool("dry-run", false, "S3, DynamoDB に書き込まずローカルに書き出す")
+	flag.Parse()
+	if *dryRun {
+		utils.DryRun = "true"
+	}
+	if utils.DryRun == "true" {
+		fmt.Printf("dry-run モードです。出力先: %s ⭐️\n", utils.DryRunDir)
+	}
 	if utils.Env == "local" {
 		fmt.Println("ローカルです⭐️")
 		handler(context.TODO())
@@ -0,0 +1,35 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/joe-black-jb/compass-reports-register/utils"
+)
+
+func main() {
+	// 調査したい XBRL ファイルまたは zip ファイルのパス
+	inspectFile := os.Getenv("INSPECT_FILE")
+	if inspectFile == "" && len(os.Args) >= 2 {
+		inspectFile = os.Args[1]
+	}
+	if inspectFile == "" {
+		log.Fatal("INSPECT_FILE が設定されていません")
+	}
+	// text もしくは json
+	format := os.Getenv("INSPECT_FORMAT")
+
+	inspection, err := utils.InspectFile(inspectFile)
+	if err != nil {
+		log.Fatal("InspectFile error: ", err)
+	}
+
+	err = utils.PrintInspection(os.Stdout, inspection, format)
+	if err != nil {
+		fmt.Println("PrintInspection error: ", err)
+	}
+}
@@ -16,7 +16,7 @@ import (
 	"github.com/joho/godotenv"
 )
 
-func main(){
+func main() {
 	fmt.Println("特定の資料のみ登録します⭐️")
 
 	env := os.Getenv("ENV")
@@ -32,7 +32,7 @@ func main(){
 
 	cfg, cfgErr := config.LoadDefaultConfig(context.TODO())
 	if cfgErr != nil {
-		fmt.Println("Load default config error: %v", cfgErr)
+		fmt.Printf("Load default config error: %v\n", cfgErr)
 		return
 	}
 	dynamoClient := dynamodb.NewFromConfig(cfg)
@@ -54,7 +54,22 @@ func main(){
 		Liabilities:     0,
 		NetAssets:       0,
 	}
+	report := utils.Result{
+		EdinetCode: singleEDINETCode,
+		DocId:      singleDocID,
+		DateKey:    singleDateKey,
+		FilerName:  companyName,
+		// "1" の場合は PDF, 添付文書, 英文ファイルも登録する
+		PdfFlag:        os.Getenv("SINGLE_PDF_FLAG"),
+		AttachDocFlag:  os.Getenv("SINGLE_ATTACH_DOC_FLAG"),
+		EnglishDocFlag: os.Getenv("SINGLE_ENGLISH_DOC_FLAG"),
+	}
 	var singleWg sync.WaitGroup
-	utils.RegisterReport(dynamoClient, singleEDINETCode, singleDocID, singleDateKey, companyName, periodStart, periodEnd, &fundamental, &singleWg)
+	// 投資信託の場合はファンドコードを指定する
+	if fundCode := os.Getenv("SINGLE_FUND_CODE"); fundCode != "" {
+		report.FundCode = fundCode
+		utils.RegisterFundReport(report, periodStart, periodEnd, &singleWg)
+		return
+	}
+	utils.RegisterReport(dynamoClient, report, periodStart, periodEnd, &fundamental, &singleWg)
 }
-
@@ -0,0 +1,261 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+/*
+代替書面・添付文書 (type=3) をダウンロードし、EDINET バケットの {dateKey}/{docID}/attach/ 配下に登録する
+*/
+func RegisterAttachments(client *http.Client, docID string, dateKey string, companyName string) {
+	prefix := fmt.Sprintf("%s/%s/attach/", dateKey, docID)
+	isRegistered, err := CheckExistsS3Key(S3Client, EDINETBucketName, prefix)
+	if err != nil {
+		fmt.Println("添付文書の存在チェック時のエラー❗️: ", err)
+	}
+	if isRegistered {
+		return
+	}
+
+	ApiTimes += 1
+	zipBody, err := DownloadDocument(client, docID, 3)
+	if err != nil {
+		ErrMsg = "添付文書ダウンロードエラー: "
+		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+		return
+	}
+	files, err := UnzipFiles(zipBody)
+	if err != nil {
+		ErrMsg = "添付文書の解凍エラー: "
+		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+		return
+	}
+	putArchiveFiles(docID, dateKey, prefix, files)
+	fmt.Printf("「%s」の添付文書を %d 件登録しました ⭕️\n", companyName, len(files))
+}
+
+/*
+英文ファイル (type=4) をダウンロードし、EDINET バケットの {dateKey}/{docID}/english/ 配下に登録する
+英文の財務諸表があれば勘定科目を抽出し、{EDINETコード}/EN/ に登録する
+*/
+func RegisterEnglishDocuments(client *http.Client, EDINETCode string, docID string, dateKey string, companyName string, periodStart string, periodEnd string, objectKeys []string) {
+	prefix := fmt.Sprintf("%s/%s/english/", dateKey, docID)
+	isRegistered, err := CheckExistsS3Key(S3Client, EDINETBucketName, prefix)
+	if err != nil {
+		fmt.Println("英文ファイルの存在チェック時のエラー❗️: ", err)
+	}
+	isExtracted := false
+	for _, key := range objectKeys {
+		if strings.Contains(key, fmt.Sprintf("/EN/%s-%s-EN-", EDINETCode, docID)) {
+			isExtracted = true
+			break
+		}
+	}
+	if isRegistered && isExtracted {
+		return
+	}
+
+	ApiTimes += 1
+	zipBody, err := DownloadDocument(client, docID, 4)
+	if err != nil {
+		ErrMsg = "英文ファイルダウンロードエラー: "
+		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+		return
+	}
+	files, err := UnzipFiles(zipBody)
+	if err != nil {
+		ErrMsg = "英文ファイルの解凍エラー: "
+		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+		return
+	}
+	if !isRegistered {
+		putArchiveFiles(docID, dateKey, prefix, files)
+		fmt.Printf("「%s」の英文ファイルを %d 件登録しました ⭕️\n", companyName, len(files))
+	}
+
+	// 英文ファイルは PDF のみの場合が多く、その場合は勘定科目を抽出できない
+	if len(EnglishHTMLFiles(files)) == 0 {
+		fmt.Printf("「%s」の英文ファイルに HTML がないため英文の勘定科目は抽出しません (%s)\n", companyName, strings.Join(archiveFileNames(files), ", "))
+		return
+	}
+	statements := FindEnglishStatements(files)
+	if len(statements) == 0 {
+		fmt.Printf("「%s」の英文ファイルに財務諸表が見つかりませんでした\n", companyName)
+		return
+	}
+	englishSummary := EnglishSummary{
+		CompanyName: companyName,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		Statements:  statements,
+	}
+	englishJSONBody, err := CreateJSON(docID, dateKey, englishSummary)
+	if err != nil {
+		return
+	}
+	var englishWg sync.WaitGroup
+	if Parallel == "true" {
+		englishWg.Add(1)
+	}
+	englishFileNamePattern := fmt.Sprintf("%s-%s-EN-from-%s-to-%s", EDINETCode, docID, periodStart, periodEnd)
+	PutFileToS3(docID, dateKey, EDINETCode, companyName, englishFileNamePattern, "json", englishJSONBody, objectKeys, &englishWg)
+}
+
+/*
+メモリ上の ZIP ファイルからすべてのファイルを取得する
+*/
+func UnzipFiles(data []byte) ([]ArchiveFile, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip file: %v", err)
+	}
+
+	var files []ArchiveFile
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		body, err := ReadWithLimit(rc, MaxDocumentBytes)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, ArchiveFile{
+			Path: f.Name,
+			Body: body,
+		})
+	}
+	return files, nil
+}
+
+// ZIP 内のファイルを ZIP 内のパスのまま prefix 配下に登録する (別のフォルダの同名ファイルを上書きしないようにする)
+func putArchiveFiles(docID string, dateKey string, prefix string, files []ArchiveFile) {
+	for _, file := range files {
+		contentType := mime.TypeByExtension(filepath.Ext(file.Path))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		key := prefix + ArchiveRelativePath(file.Path)
+		err := PutDocumentToEDINETBucket(key, file.Body, contentType)
+		if err != nil {
+			ErrMsg = "S3 への添付ファイル送信エラー: "
+			RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+		}
+	}
+}
+
+/*
+英文ファイルのうち HTML (.htm, .html) のファイルを返す
+*/
+func EnglishHTMLFiles(files []ArchiveFile) []ArchiveFile {
+	var htmlFiles []ArchiveFile
+	for _, file := range files {
+		ext := strings.ToLower(filepath.Ext(file.Path))
+		if ext == ".htm" || ext == ".html" {
+			htmlFiles = append(htmlFiles, file)
+		}
+	}
+	return htmlFiles
+}
+
+func archiveFileNames(files []ArchiveFile) []string {
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, ArchiveRelativePath(file.Path))
+	}
+	return names
+}
+
+/*
+英文ファイルの HTML から BS, PL, CF のテーブルを探す
+財務諸表ごとに最初に見つかったテーブルを使う (PDF などの HTML 以外のファイルは対象外)
+*/
+func FindEnglishStatements(files []ArchiveFile) []EnglishStatement {
+	var statements []EnglishStatement
+	found := make(map[string]bool)
+	for _, file := range EnglishHTMLFiles(files) {
+		doc, err := goquery.NewDocumentFromReader(bytes.NewReader(file.Body))
+		if err != nil {
+			fmt.Printf("%s の goquery.NewDocumentFromReader error: %v\n", file.Path, err)
+			continue
+		}
+		doc.Find("table").Each(func(i int, table *goquery.Selection) {
+			statementType := ClassifyEnglishTable(table.Text())
+			if statementType == "" || found[statementType] {
+				return
+			}
+			tableHTML, err := goquery.OuterHtml(table)
+			if err != nil {
+				return
+			}
+			found[statementType] = true
+			statements = append(statements, EnglishStatement{
+				StatementType: statementType,
+				FileName:      filepath.Base(file.Path),
+				Labels:        englishTableLabels(table),
+				HTML:          FormatHtmlTable(tableHTML),
+			})
+		})
+	}
+	return statements
+}
+
+/*
+英文のテーブルの種類を判定する
+@returns
+
+	BS, PL, CF のいずれか (該当しない場合は空文字)
+*/
+func ClassifyEnglishTable(text string) string {
+	lowerText := strings.ToLower(strings.Join(strings.Fields(text), " "))
+	switch {
+	case strings.Contains(lowerText, "cash flows from operating activities"):
+		return "CF"
+	case strings.Contains(lowerText, "total assets") &&
+		(strings.Contains(lowerText, "total liabilities") || strings.Contains(lowerText, "total net assets") || strings.Contains(lowerText, "total equity")):
+		return "BS"
+	case strings.Contains(lowerText, "operating income") || strings.Contains(lowerText, "operating profit") || strings.Contains(lowerText, "ordinary income"):
+		return "PL"
+	}
+	return ""
+}
+
+// 各行の最初の空でないセルを勘定科目とする
+func englishTableLabels(table *goquery.Selection) []string {
+	var labels []string
+	table.Find("tr").Each(func(i int, tr *goquery.Selection) {
+		tr.Find("td, th").EachWithBreak(func(j int, cell *goquery.Selection) bool {
+			text := strings.Join(strings.Fields(cell.Text()), " ")
+			if text == "" {
+				return true
+			}
+			labels = append(labels, text)
+			return false
+		})
+	})
+	return labels
+}
+
+/*
+ZIP 内のパスを ZIP のルートからの相対パス (S3 のキー) にする
+区切り文字を / にそろえ、ZIP の外を指す ../ は取り除く
+例: XBRL\AttachDoc\0101010.htm → XBRL/AttachDoc/0101010.htm, ../../header.htm → header.htm
+*/
+func ArchiveRelativePath(name string) string {
+	cleaned := path.Clean("/" + strings.ReplaceAll(name, "\\", "/"))
+	return strings.TrimPrefix(cleaned, "/")
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// ZIP ファイルを作成する (パス → 中身)
+func createTestZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, body := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+const englishBSTable = `<html><body><table>
+<tr><td>Total current assets</td><td>1,000</td><td>1,200</td></tr>
+<tr><td>Total assets</td><td>3,000</td><td>3,500</td></tr>
+<tr><td>Total liabilities</td><td>1,500</td><td>1,600</td></tr>
+<tr><td>Total net assets</td><td>1,500</td><td>1,900</td></tr>
+</table></body></html>`
+
+func TestFindEnglishStatements(t *testing.T) {
+	tests := []struct {
+		name          string
+		files         map[string]string
+		wantHTMLFiles int
+		wantTypes     []string
+	}{
+		{
+			name:          "PDF のみ",
+			files:         map[string]string{"S100TEST/EnglishDoc/S100TEST_E00001_en.pdf": "%PDF-1.4"},
+			wantHTMLFiles: 0,
+		},
+		{
+			name: "HTML の貸借対照表",
+			files: map[string]string{
+				"S100TEST/EnglishDoc/0105010_honbun_en.htm": englishBSTable,
+				"S100TEST/EnglishDoc/S100TEST_en.pdf":       "%PDF-1.4",
+			},
+			wantHTMLFiles: 1,
+			wantTypes:     []string{"BS"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := UnzipFiles(createTestZip(t, tt.files))
+			if err != nil {
+				t.Fatalf("UnzipFiles error: %v", err)
+			}
+			if got := len(EnglishHTMLFiles(files)); got != tt.wantHTMLFiles {
+				t.Errorf("EnglishHTMLFiles = %d files, want %d", got, tt.wantHTMLFiles)
+			}
+			var gotTypes []string
+			for _, statement := range FindEnglishStatements(files) {
+				gotTypes = append(gotTypes, statement.StatementType)
+			}
+			if !reflect.DeepEqual(gotTypes, tt.wantTypes) {
+				t.Errorf("FindEnglishStatements types = %v, want %v", gotTypes, tt.wantTypes)
+			}
+		})
+	}
+}
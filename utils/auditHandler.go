@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"fmt"
+	"html"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// 監査意見の種類
+const (
+	OpinionUnqualified = "無限定適正意見"
+	OpinionQualified   = "限定付適正意見"
+	OpinionAdverse     = "不適正意見"
+	OpinionDisclaimer  = "意見不表明"
+)
+
+// タグを取り除くためのパターン
+var TagRe = regexp.MustCompile(`<[^>]*>`)
+
+// 監査法人名のパターン (例: 有限責任監査法人トーマツ, EY新日本有限責任監査法人)
+var AuditFirmRe = regexp.MustCompile(`[^\s　、。，,「」『』（）()：:]*監査法人[^\s　、。，,「」『』（）()：:は]*`)
+
+/*
+監査報告書の XBRL インスタンスから監査人と監査意見を取得する
+*/
+func ParseAuditReport(instance XBRLInstance) AuditReport {
+	// TextBlock のエスケープを戻し、タグを除いたテキストにする
+	text := html.UnescapeString(string(instance.Body))
+	text = TagRe.ReplaceAllString(text, "\n")
+
+	return AuditReport{
+		FileName:    filepath.Base(instance.Path),
+		AuditorName: FindAuditorName(text),
+		OpinionType: FindOpinionType(text),
+	}
+}
+
+/*
+監査報告書のテキストから監査法人名を取得する
+「当監査法人」などの自称を除き、最も多く出現したものを監査法人名とする
+*/
+func FindAuditorName(text string) string {
+	counts := make(map[string]int)
+	var order []string
+	for _, match := range AuditFirmRe.FindAllString(text, -1) {
+		if match == "監査法人" || strings.HasPrefix(match, "当") {
+			continue
+		}
+		if counts[match] == 0 {
+			order = append(order, match)
+		}
+		counts[match]++
+	}
+
+	var auditorName string
+	for _, name := range order {
+		if counts[name] > counts[auditorName] {
+			auditorName = name
+		}
+	}
+	return auditorName
+}
+
+/*
+監査報告書のテキストから監査意見の種類を判定する
+限定付適正意見の報告書にも「適正に表示している」が含まれるため、判定は厳しい意見から順に行う
+*/
+func FindOpinionType(text string) string {
+	switch {
+	case strings.Contains(text, "意見不表明") || strings.Contains(text, "意見を表明しない"):
+		return OpinionDisclaimer
+	case strings.Contains(text, "不適正意見"):
+		return OpinionAdverse
+	case strings.Contains(text, "限定付適正意見"):
+		return OpinionQualified
+	case strings.Contains(text, "適正に表示している"):
+		return OpinionUnqualified
+	}
+	return ""
+}
+
+/*
+監査報告書の一覧からサマリーを作成する
+*/
+func CreateAuditSummary(companyName string, periodStart string, periodEnd string, audits []XBRLInstance) AuditSummary {
+	auditSummary := AuditSummary{
+		CompanyName: companyName,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+	}
+	for _, audit := range audits {
+		auditReport := ParseAuditReport(audit)
+		fmt.Printf("「%s」の監査報告書 (%s): %s / %s\n", companyName, auditReport.FileName, auditReport.AuditorName, auditReport.OpinionType)
+		auditSummary.Audits = append(auditSummary.Audits, auditReport)
+	}
+	return auditSummary
+}
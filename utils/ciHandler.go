@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+/*
+損益計算書と別に包括利益計算書がある場合、その他の包括利益・包括利益を損益計算書のサマリーに設定し、
+HTML を {EDINETコード}/CI/consolidated/ 配下に登録する
+損益及び包括利益計算書 (1 計算書方式) の場合は損益計算書のパース時に設定済みのため何もしない
+*/
+func RegisterCIStatement(docID string, dateKey string, EDINETCode string, companyName string, periodStart string, periodEnd string, matches StatementMatches, plMatch string, plSummary *PLSummary, fundamental *Fundamental, labelDictionary *LabelDictionary, objectKeys []string) {
+	ciLabel, ciMatch := SelectStatementMatch("CI", matches)
+	if ciMatch == "" || ciMatch == plMatch {
+		return
+	}
+
+	ciHTMLBody := []byte(matches.StatementHTML(ciMatch))
+	ciDoc, err := goquery.NewDocumentFromReader(bytes.NewReader(ciHTMLBody))
+	if err != nil {
+		ErrMsg = "CI goquery.NewDocumentFromReader error: "
+		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+		return
+	}
+	UpdateEverySummary(ciDoc, docID, dateKey, "pl", nil, plSummary, nil, fundamental, labelDictionary, nil)
+	fmt.Printf("「%s」の%sから包括利益を取得しました (その他の包括利益: %d, 包括利益: %d)\n", companyName, ciLabel, plSummary.OtherComprehensiveIncome.Current, plSummary.ComprehensiveIncome.Current)
+
+	var ciWg sync.WaitGroup
+	if Parallel == "true" {
+		ciWg.Add(1)
+	}
+	ciFileNamePattern := fmt.Sprintf("%s-%s-CI-from-%s-to-%s", EDINETCode, docID, periodStart, periodEnd)
+	PutScopedFileToS3(docID, dateKey, EDINETCode, companyName, StatementScope(ciLabel), ciFileNamePattern, "html", ciHTMLBody, objectKeys, &ciWg)
+}
@@ -0,0 +1,234 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 前期・当期の列を探す見出し行の数
+const maxHeaderRows = 5
+
+// 前期・当期の列の見出し
+var (
+	PreviousColumnLabels = []string{"前連結会計年度", "前事業年度", "前連結会計期間", "前会計期間", "前中間連結会計期間", "前中間会計期間", "前第", "前期"}
+	CurrentColumnLabels  = []string{"当連結会計年度", "当事業年度", "当連結会計期間", "当会計期間", "当中間連結会計期間", "当中間会計期間", "当第", "当期"}
+)
+
+// 見出しの日付 (例: 2024年3月31日)
+var headerDateRe = regexp.MustCompile(`([0-9]{4})年([0-9]{1,2})月([0-9]{1,2})日`)
+
+// 表の列の範囲 (colspan・rowspan を考慮した位置で、start 以上 end 未満)
+type columnRange struct {
+	start int
+	end   int
+}
+
+// 財務諸表の表の前期・当期の列
+type StatementColumns struct {
+	Previous columnRange
+	Current  columnRange
+	Width    int // 見出し行の列数
+}
+
+// colspan・rowspan を考慮した td の位置
+type gridCell struct {
+	columnRange
+	text string
+}
+
+/*
+見出し行から前期・当期の列を探す
+「前連結会計年度」「当事業年度」などの見出しを優先し、見つからない場合は見出しの日付 (古い方を前期) で判定する
+@returns
+
+	見つからない場合は false (td のテキストの順番で値を取得する)
+*/
+func DetectStatementColumns(doc *goquery.Document) (StatementColumns, bool) {
+	var columns StatementColumns
+	var datedCells []gridCell
+	var dates []string
+	rows := doc.Find("tr")
+	for i := 0; i < rows.Length() && i < maxHeaderRows; i++ {
+		var previous, current *gridCell
+		cells := tableRowCells(rows.Eq(i))
+		for _, cell := range cells {
+			// 1 列目 (勘定科目) は対象外
+			if cell.start == 0 {
+				continue
+			}
+			text := removeSpaces(jpNumberReplacer.Replace(cell.text))
+			switch {
+			case previous == nil && hasAnyPrefix(text, PreviousColumnLabels):
+				previous = &cell
+			case current == nil && hasAnyPrefix(text, CurrentColumnLabels):
+				current = &cell
+			}
+			if dateMatches := headerDateRe.FindAllStringSubmatch(text, -1); len(dateMatches) > 0 {
+				datedCells = append(datedCells, cell)
+				dates = append(dates, sortableDate(dateMatches[len(dateMatches)-1]))
+				columns.Width = rowWidth(cells)
+			}
+		}
+		if previous != nil && current != nil && previous.start != current.start {
+			columns.Previous = previous.columnRange
+			columns.Current = current.columnRange
+			columns.Width = rowWidth(cells)
+			return columns, true
+		}
+	}
+
+	// 見出しの日付 (期間の場合は末日) で判定する
+	if len(datedCells) != 2 || dates[0] == dates[1] || datedCells[0].start == datedCells[1].start {
+		return columns, false
+	}
+	previousIndex, currentIndex := 0, 1
+	if dates[0] > dates[1] {
+		previousIndex, currentIndex = 1, 0
+	}
+	columns.Previous = datedCells[previousIndex].columnRange
+	columns.Current = datedCells[currentIndex].columnRange
+	return columns, true
+}
+
+/*
+行の前期・当期の列のテキストを取得する
+見出しの列が複数の td にまたがる場合は、範囲内の最も右の空でない td を使う
+見出し行と列数が異なる場合 (見出し行に注記の列がないなど) は右端をそろえる
+@returns
+
+	前期・当期のいずれかの列が空の場合は false
+*/
+func (c StatementColumns) CellTexts(tr *goquery.Selection) (string, string, bool) {
+	cells := tableRowCells(tr)
+	offset := rowWidth(cells) - c.Width
+	previousText := cellTextInRange(cells, c.Previous.shift(offset))
+	currentText := cellTextInRange(cells, c.Current.shift(offset))
+	return previousText, currentText, previousText != "" && currentText != ""
+}
+
+func (r columnRange) shift(offset int) columnRange {
+	return columnRange{start: r.start + offset, end: r.end + offset}
+}
+
+func rowWidth(cells []gridCell) int {
+	if len(cells) == 0 {
+		return 0
+	}
+	return cells[len(cells)-1].end
+}
+
+func cellTextInRange(cells []gridCell, columns columnRange) string {
+	var text string
+	for _, cell := range cells {
+		// 1 列目 (勘定科目) は対象外
+		if cell.start == 0 || cell.end <= columns.start || cell.start >= columns.end {
+			continue
+		}
+		if cellText := strings.TrimSpace(cell.text); cellText != "" {
+			text = cellText
+		}
+	}
+	return text
+}
+
+// 行の td を、同じ表の上の行の rowspan を考慮した位置とともに取得する
+func tableRowCells(tr *goquery.Selection) []gridCell {
+	rows := tr.Closest("table").Find("tr")
+	index := rows.IndexOfSelection(tr)
+	if index < 0 {
+		return gridCells(tr, make(map[int]int))
+	}
+	occupied := make(map[int]int)
+	var cells []gridCell
+	rows.Slice(0, index+1).Each(func(i int, row *goquery.Selection) {
+		cells = gridCells(row, occupied)
+	})
+	return cells
+}
+
+/*
+行の td を colspan を考慮した位置とともに取得する
+上の行の rowspan で埋まっている列は空のセルとする
+@params
+
+	occupied: 上の行の rowspan で埋まっている列 (列 → 残りの行数)。この行の分を減らし、この行の rowspan を加える
+*/
+func gridCells(tr *goquery.Selection, occupied map[int]int) []gridCell {
+	var cells []gridCell
+	column := 0
+	addOccupied := func() {
+		cells = append(cells, gridCell{columnRange: columnRange{start: column, end: column + 1}})
+		column++
+	}
+	// この行の rowspan (列 → 次の行以降の行数)
+	rowspans := make(map[int]int)
+	tr.Find("td, th").Each(func(i int, td *goquery.Selection) {
+		for occupied[column] > 0 {
+			addOccupied()
+		}
+		colspan := spanAttr(td, "colspan")
+		if rowspan := spanAttr(td, "rowspan"); rowspan > 1 {
+			for c := column; c < column+colspan; c++ {
+				rowspans[c] = rowspan - 1
+			}
+		}
+		cells = append(cells, gridCell{
+			columnRange: columnRange{start: column, end: column + colspan},
+			text:        strings.Join(strings.Fields(td.Text()), " "),
+		})
+		column += colspan
+	})
+	// 右端の rowspan で埋まっている列
+	lastOccupied := -1
+	for c := range occupied {
+		lastOccupied = max(lastOccupied, c)
+	}
+	for column <= lastOccupied {
+		if occupied[column] > 0 {
+			addOccupied()
+		} else {
+			column++
+		}
+	}
+
+	for c := range occupied {
+		occupied[c]--
+		if occupied[c] <= 0 {
+			delete(occupied, c)
+		}
+	}
+	for c, rows := range rowspans {
+		occupied[c] = rows
+	}
+	return cells
+}
+
+// colspan・rowspan の値 (指定がない場合は 1)
+func spanAttr(td *goquery.Selection, name string) int {
+	if value, ok := td.Attr(name); ok {
+		if span, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && span > 1 {
+			return span
+		}
+	}
+	return 1
+}
+
+func hasAnyPrefix(text string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(text, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// 比較用の日付文字列 (例: 2024-03-31)
+func sortableDate(match []string) string {
+	month, _ := strconv.Atoi(match[2])
+	day, _ := strconv.Atoi(match[3])
+	return fmt.Sprintf("%s-%02d-%02d", match[1], month, day)
+}
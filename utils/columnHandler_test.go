@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 見出し行と最後の行 (金額の行) から前期・当期の値を取得する
+func TestDetectStatementColumns(t *testing.T) {
+	tests := []struct {
+		name         string
+		rows         string
+		wantOK       bool
+		wantPrevious string
+		wantCurrent  string
+	}{
+		{
+			name: "前期・当期の見出し",
+			rows: `<tr><td></td><td>前連結会計年度 (2023年3月31日)</td><td>当連結会計年度 (2024年3月31日)</td></tr>
+<tr><td>売上高</td><td>100</td><td>200</td></tr>`,
+			wantOK: true, wantPrevious: "100", wantCurrent: "200",
+		},
+		{
+			name: "注記の列",
+			rows: `<tr><td></td><td>注記番号</td><td>前事業年度</td><td>当事業年度</td></tr>
+<tr><td>売上原価</td><td>※1</td><td>100</td><td>200</td></tr>`,
+			wantOK: true, wantPrevious: "100", wantCurrent: "200",
+		},
+		{
+			name: "見出し行にない注記の列 (右端をそろえる)",
+			rows: `<tr><td></td><td>前事業年度</td><td>当事業年度</td></tr>
+<tr><td>売上原価</td><td>※1</td><td>100</td><td>200</td></tr>`,
+			wantOK: true, wantPrevious: "100", wantCurrent: "200",
+		},
+		{
+			name: "colspan の見出し",
+			rows: `<tr><td></td><td colspan="2">前連結会計年度</td><td colspan="2">当連結会計年度</td></tr>
+<tr><td>売上高</td><td></td><td>100</td><td>※2</td><td>200</td></tr>`,
+			wantOK: true, wantPrevious: "100", wantCurrent: "200",
+		},
+		{
+			name: "rowspan の勘定科目の見出しの次の行に前期・当期の見出し",
+			rows: `<tr><td rowspan="2">区分</td><td colspan="2">(単位：百万円)</td></tr>
+<tr><td>前連結会計年度</td><td>当連結会計年度</td></tr>
+<tr><td>売上高</td><td>100</td><td>200</td></tr>`,
+			wantOK: true, wantPrevious: "100", wantCurrent: "200",
+		},
+		{
+			name: "rowspan の勘定科目の見出しの次の行に日付",
+			rows: `<tr><td rowspan="2">区分</td><td>前連結会計年度</td><td rowspan="2">当連結会計年度</td></tr>
+<tr><td>2023年3月31日</td></tr>
+<tr><td>売上高</td><td>100</td><td>200</td></tr>`,
+			wantOK: true, wantPrevious: "100", wantCurrent: "200",
+		},
+		{
+			name: "rowspan の注記の列",
+			rows: `<tr><td rowspan="2"></td><td rowspan="2">注記</td><td colspan="2">金額</td></tr>
+<tr><td>2023年3月31日</td><td>2024年3月31日</td></tr>
+<tr><td>売上高</td><td>※1</td><td>100</td><td>200</td></tr>`,
+			wantOK: true, wantPrevious: "100", wantCurrent: "200",
+		},
+		{
+			name: "日付のみの見出し (当期が左)",
+			rows: `<tr><td></td><td>2024年3月31日</td><td>2023年3月31日</td></tr>
+<tr><td>売上高</td><td>200</td><td>100</td></tr>`,
+			wantOK: true, wantPrevious: "100", wantCurrent: "200",
+		},
+		{
+			name: "期間の見出し (末日で判定)",
+			rows: `<tr><td></td><td>(自 2023年4月1日 至 2024年3月31日)</td><td>(自 2022年4月1日 至 2023年3月31日)</td></tr>
+<tr><td>売上高</td><td>200</td><td>100</td></tr>`,
+			wantOK: true, wantPrevious: "100", wantCurrent: "200",
+		},
+		{
+			name: "全角数字の日付",
+			rows: `<tr><td></td><td>２０２３年３月３１日</td><td>２０２４年３月３１日</td></tr>
+<tr><td>売上高</td><td>100</td><td>200</td></tr>`,
+			wantOK: true, wantPrevious: "100", wantCurrent: "200",
+		},
+		{
+			name: "見出しがない",
+			rows: `<tr><td>売上高</td><td>100</td><td>200</td></tr>`,
+		},
+		{
+			name: "日付が同じ",
+			rows: `<tr><td></td><td>2024年3月31日</td><td>2024年3月31日</td></tr>
+<tr><td>売上高</td><td>100</td><td>200</td></tr>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader("<table>" + tt.rows + "</table>"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			columns, ok := DetectStatementColumns(doc)
+			if ok != tt.wantOK {
+				t.Fatalf("DetectStatementColumns() ok = %v, want %v (%+v)", ok, tt.wantOK, columns)
+			}
+			if !ok {
+				return
+			}
+			previous, current, hasValue := columns.CellTexts(doc.Find("tr").Last())
+			if previous != tt.wantPrevious || current != tt.wantCurrent || !hasValue {
+				t.Errorf("CellTexts() = (%q, %q, %v), want (%q, %q, true)", previous, current, hasValue, tt.wantPrevious, tt.wantCurrent)
+			}
+		})
+	}
+}
+
+func TestCellTexts(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<table>
+<tr><td></td><td>前事業年度</td><td>当事業年度</td></tr>
+<tr><td>流動資産</td><td></td><td></td></tr>
+<tr><td rowspan="2">売掛金</td><td>100</td><td>200</td></tr>
+<tr><td>△1</td><td>△2</td></tr>
+<tr><td>現金及び預金</td><td>300</td><td></td></tr>
+</table>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	columns, ok := DetectStatementColumns(doc)
+	if !ok {
+		t.Fatal("DetectStatementColumns() ok = false")
+	}
+	tests := []struct {
+		name         string
+		row          int
+		wantPrevious string
+		wantCurrent  string
+		wantOK       bool
+	}{
+		{"値のない見出し行", 1, "", "", false},
+		{"金額の行", 2, "100", "200", true},
+		{"rowspan の勘定科目の次の行", 3, "△1", "△2", true},
+		{"当期が空欄", 4, "300", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous, current, ok := columns.CellTexts(doc.Find("tr").Eq(tt.row))
+			if previous != tt.wantPrevious || current != tt.wantCurrent || ok != tt.wantOK {
+				t.Errorf("CellTexts() = (%q, %q, %v), want (%q, %q, %v)", previous, current, ok, tt.wantPrevious, tt.wantCurrent, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,530 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf16"
+)
+
+// "csv" の場合、サマリーを EDINET の CSV (type=5) の値で上書きし、HTML から取得した値との差分を S3 に登録する
+var ExtractionSource string
+
+// EDINET CSV の 1 行
+type CSVRow struct {
+	ElementID     string // 要素ID (例: jppfs_cor:NetSales)
+	ItemName      string // 項目名
+	ContextID     string // コンテキストID (例: CurrentYearDuration)
+	RelativeYear  string // 相対年度
+	Consolidation string // 連結・個別
+	PeriodType    string // 期間・時点
+	UnitID        string // ユニットID
+	Unit          string // 単位
+	Value         string // 値
+}
+
+// CSV の要素 ID とサマリーの項目の対応
+type CSVSummaryField struct {
+	Statement  string   // bs, pl, cf
+	Field      string   // サマリーの json フィールド名
+	ElementIDs []string // 優先順に並べた要素 ID (日本基準, IFRS)
+	Period     string   // Instant (時点) もしくは Duration (期間)
+}
+
+var CSVSummaryFields = []CSVSummaryField{
+	// 貸借対照表
+	{"bs", "current_assets", []string{"jppfs_cor:CurrentAssets", "jpigp_cor:CurrentAssetsIFRS"}, "Instant"},
+	{"bs", "tangible_assets", []string{"jppfs_cor:PropertyPlantAndEquipment", "jpigp_cor:PropertyPlantAndEquipmentIFRS"}, "Instant"},
+	{"bs", "intangible_assets", []string{"jppfs_cor:IntangibleAssets", "jpigp_cor:IntangibleAssetsIFRS"}, "Instant"},
+	{"bs", "investments_and_other_assets", []string{"jppfs_cor:InvestmentsAndOtherAssets"}, "Instant"},
+	{"bs", "current_liabilities", []string{"jppfs_cor:CurrentLiabilities", "jpigp_cor:TotalCurrentLiabilitiesIFRS"}, "Instant"},
+	{"bs", "fixed_liabilities", []string{"jppfs_cor:NoncurrentLiabilities", "jpigp_cor:NonCurrentLiabilitiesIFRS"}, "Instant"},
+	{"bs", "net_assets", []string{"jppfs_cor:NetAssets", "jpigp_cor:EquityIFRS"}, "Instant"},
+	{"bs", "liabilities", []string{"jppfs_cor:Liabilities", "jpigp_cor:LiabilitiesIFRS"}, "Instant"},
+	{"bs", "total_assets", []string{"jppfs_cor:Assets", "jpigp_cor:AssetsIFRS"}, "Instant"},
+	{"bs", "shareholders_equity", []string{"jppfs_cor:ShareholdersEquity"}, "Instant"},
+	{"bs", "retained_earnings", []string{"jppfs_cor:RetainedEarnings", "jpigp_cor:RetainedEarningsIFRS"}, "Instant"},
+	{"bs", "non_controlling_interests", []string{"jppfs_cor:NonControllingInterests", "jpigp_cor:NonControllingInterestsIFRS"}, "Instant"},
+	{"bs", "subscription_rights", []string{"jppfs_cor:SubscriptionRightsToShares"}, "Instant"},
+	{"bs", "owners_equity", []string{"jpigp_cor:EquityAttributableToOwnersOfParentIFRS"}, "Instant"},
+	{"bs", "cash_and_deposits", []string{"jppfs_cor:CashAndDeposits", "jpigp_cor:CashAndCashEquivalentsIFRS"}, "Instant"},
+	{"bs", "short_term_borrowings", []string{"jppfs_cor:ShortTermLoansPayable"}, "Instant"},
+	{"bs", "treasury_stock", []string{"jppfs_cor:TreasuryStock", "jpigp_cor:TreasurySharesIFRS"}, "Instant"},
+	{"bs", "inventories", []string{"jppfs_cor:Inventories", "jpigp_cor:InventoriesIFRS"}, "Instant"},
+	{"bs", "long_term_borrowings", []string{"jppfs_cor:LongTermLoansPayable"}, "Instant"},
+	{"bs", "bonds", []string{"jppfs_cor:BondsPayable"}, "Instant"},
+	{"bs", "bonds_and_borrowings", []string{"jpigp_cor:BondsAndBorrowingsNCLIFRS"}, "Instant"},
+	// 損益計算書
+	{"pl", "cost_of_goods_sold", []string{"jppfs_cor:CostOfSales", "jpigp_cor:CostOfSalesIFRS"}, "Duration"},
+	{"pl", "sg_and_a", []string{"jppfs_cor:SellingGeneralAndAdministrativeExpenses", "jpigp_cor:SellingGeneralAndAdministrativeExpensesIFRS"}, "Duration"},
+	{"pl", "sales", []string{"jppfs_cor:NetSales"}, "Duration"},
+	{"pl", "operating_profit", []string{"jppfs_cor:OperatingIncome", "jpigp_cor:OperatingProfitLossIFRS"}, "Duration"},
+	// IFRS の売上収益は勘定科目の辞書 (SummaryLabelFields) と同じく営業収益とする
+	{"pl", "operating_revenue", []string{"jppfs_cor:OperatingRevenue1", "jppfs_cor:OperatingRevenue2", "jpigp_cor:RevenueIFRS"}, "Duration"},
+	{"pl", "operating_cost", []string{"jppfs_cor:OperatingExpenses"}, "Duration"},
+	{"pl", "non_operating_income", []string{"jppfs_cor:NonOperatingIncome"}, "Duration"},
+	{"pl", "non_operating_expenses", []string{"jppfs_cor:NonOperatingExpenses"}, "Duration"},
+	{"pl", "ordinary_profit", []string{"jppfs_cor:OrdinaryIncome"}, "Duration"},
+	{"pl", "extraordinary_income", []string{"jppfs_cor:ExtraordinaryIncome"}, "Duration"},
+	{"pl", "extraordinary_loss", []string{"jppfs_cor:ExtraordinaryLoss"}, "Duration"},
+	{"pl", "profit_before_tax", []string{"jppfs_cor:IncomeBeforeIncomeTaxes", "jpigp_cor:ProfitLossBeforeTaxIFRS"}, "Duration"},
+	{"pl", "income_taxes", []string{"jppfs_cor:IncomeTaxes", "jpigp_cor:IncomeTaxExpenseIFRS"}, "Duration"},
+	{"pl", "net_income", []string{"jppfs_cor:ProfitLoss", "jpigp_cor:ProfitLossIFRS"}, "Duration"},
+	{"pl", "net_income_attributable_to_owners", []string{"jppfs_cor:ProfitLossAttributableToOwnersOfParent", "jpigp_cor:ProfitLossAttributableToOwnersOfParentIFRS"}, "Duration"},
+	{"pl", "other_comprehensive_income", []string{"jppfs_cor:OtherComprehensiveIncome", "jpigp_cor:OtherComprehensiveIncomeIFRS"}, "Duration"},
+	{"pl", "comprehensive_income", []string{"jppfs_cor:ComprehensiveIncome", "jpigp_cor:ComprehensiveIncomeIFRS"}, "Duration"},
+	{"pl", "owners_comprehensive_income", []string{"jppfs_cor:ComprehensiveIncomeAttributableToOwnersOfTheParent", "jpigp_cor:ComprehensiveIncomeAttributableToOwnersOfParentIFRS"}, "Duration"},
+	// キャッシュ・フロー計算書
+	{"cf", "operating_cf", []string{"jppfs_cor:NetCashProvidedByUsedInOperatingActivities", "jpigp_cor:NetCashProvidedByUsedInOperatingActivitiesIFRS"}, "Duration"},
+	{"cf", "investing_cf", []string{"jppfs_cor:NetCashProvidedByUsedInInvestmentActivities", "jpigp_cor:NetCashProvidedByUsedInInvestingActivitiesIFRS"}, "Duration"},
+	{"cf", "financing_cf", []string{"jppfs_cor:NetCashProvidedByUsedInFinancingActivities", "jpigp_cor:NetCashProvidedByUsedInFinancingActivitiesIFRS"}, "Duration"},
+	{"cf", "end_cash", []string{"jppfs_cor:CashAndCashEquivalents", "jpigp_cor:CashAndCashEquivalentsIFRS"}, "Instant"},
+	{"cf", "net_change", []string{"jppfs_cor:NetIncreaseDecreaseInCashAndCashEquivalents", "jpigp_cor:NetIncreaseDecreaseInCashAndCashEquivalentsIFRS"}, "Duration"},
+	{"cf", "depreciation", []string{"jppfs_cor:DepreciationAndAmortizationOpeCF", "jpigp_cor:DepreciationAndAmortizationOpeCFIFRS"}, "Duration"},
+	{"cf", "capex", []string{"jppfs_cor:PurchaseOfPropertyPlantAndEquipmentInvCF", "jpigp_cor:PurchaseOfPropertyPlantAndEquipmentInvCFIFRS"}, "Duration"},
+	{"cf", "dividends_paid", []string{"jppfs_cor:CashDividendsPaidFinCF", "jpigp_cor:DividendsPaidFinCFIFRS"}, "Duration"},
+	{"cf", "share_buybacks", []string{"jppfs_cor:PurchaseOfTreasuryStockFinCF", "jpigp_cor:PurchaseOfTreasurySharesFinCFIFRS"}, "Duration"},
+}
+
+// サマリーの項目が 1 年内返済予定の金額などを含む場合に、CSV の値に加算する要素 ID
+var CSVSummaryAddends = map[string][]string{
+	"bs.long_term_borrowings": {"jppfs_cor:CurrentPortionOfLongTermLoansPayable"},
+	"bs.bonds":                {"jppfs_cor:CurrentPortionOfBonds"},
+	"bs.bonds_and_borrowings": {"jpigp_cor:BondsAndBorrowingsCLIFRS"},
+}
+
+/*
+EDINET CSV の ZIP から有価証券報告書本体 (XBRL_TO_CSV/jpcrp*.csv) の行を取得する
+監査報告書 (jpaud*.csv) は対象外
+*/
+func ReadCSVArchive(data []byte) ([]CSVRow, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip file: %v", err)
+	}
+
+	var rows []CSVRow
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || filepath.Ext(f.Name) != ".csv" || !strings.HasPrefix(filepath.Base(f.Name), "jpcrp") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		body, err := ReadWithLimit(rc, MaxDocumentBytes)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		fileRows, err := ParseEDINETCSV(body)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", f.Name, err)
+		}
+		rows = append(rows, fileRows...)
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("ZIP 内に有価証券報告書の CSV ファイルがありません")
+	}
+	return rows, nil
+}
+
+/*
+EDINET CSV (UTF-16LE, タブ区切り) をパースする
+1 行目はヘッダーのため読み飛ばす
+*/
+func ParseEDINETCSV(body []byte) ([]CSVRow, error) {
+	reader := csv.NewReader(strings.NewReader(decodeUTF16LE(body)))
+	reader.Comma = '\t'
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+
+	var rows []CSVRow
+	isHeader := true
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if isHeader {
+			isHeader = false
+			continue
+		}
+		if len(record) < 9 {
+			continue
+		}
+		rows = append(rows, CSVRow{
+			ElementID:     record[0],
+			ItemName:      record[1],
+			ContextID:     record[2],
+			RelativeYear:  record[3],
+			Consolidation: record[4],
+			PeriodType:    record[5],
+			UnitID:        record[6],
+			Unit:          record[7],
+			Value:         record[8],
+		})
+	}
+	return rows, nil
+}
+
+// UTF-16LE (BOM あり・なし) を文字列に変換する
+func decodeUTF16LE(body []byte) string {
+	if len(body) >= 2 && body[0] == 0xFF && body[1] == 0xFE {
+		body = body[2:]
+	}
+	units := make([]uint16, len(body)/2)
+	for i := range units {
+		units[i] = uint16(body[2*i]) | uint16(body[2*i+1])<<8
+	}
+	return string(utf16.Decode(units))
+}
+
+/*
+CSV の行から要素 ID とコンテキスト ID で数値 (円) を探す
+*/
+func FindCSVValue(rows []CSVRow, elementID string, contextID string) (int64, bool) {
+	for _, row := range rows {
+		if row.ElementID != elementID || row.ContextID != contextID {
+			continue
+		}
+		value, err := strconv.ParseInt(strings.TrimSpace(row.Value), 10, 64)
+		if err != nil {
+			continue
+		}
+		return value, true
+	}
+	return 0, false
+}
+
+/*
+サマリーの単位 (百万円, 千円, 円) に合わせて円単位の値を割る数
+*/
+func UnitDivisor(unitString string) int64 {
+	switch {
+	case strings.Contains(unitString, "百万円"):
+		return 1000000
+	case strings.Contains(unitString, "千円"):
+		return 1000
+	}
+	return 1
+}
+
+/*
+CSV からサマリーの項目ごとの値を取得する (単位はサマリーに合わせ、単位未満は四捨五入する)
+HTML から単位を取得できなかった statement の項目は、円単位の値で上書きしないように取得しない
+@params
+
+	consolidated: statement (bs, pl, cf) ごとに連結の値を使うかどうか
+	units:        statement ごとの単位 (HTML から取得したもの)
+*/
+func CreateCSVValues(rows []CSVRow, consolidated map[string]bool, units map[string]string) map[string]TitleValue {
+	values := make(map[string]TitleValue)
+	for _, field := range CSVSummaryFields {
+		if units[field.Statement] == "" {
+			continue
+		}
+		suffix := ""
+		if !consolidated[field.Statement] {
+			suffix = "_NonConsolidatedMember"
+		}
+		divisor := UnitDivisor(units[field.Statement])
+
+		for _, elementID := range field.ElementIDs {
+			previous, hasPrevious := FindCSVValue(rows, elementID, "Prior1Year"+field.Period+suffix)
+			current, hasCurrent := FindCSVValue(rows, elementID, "CurrentYear"+field.Period+suffix)
+			if !hasPrevious && !hasCurrent {
+				continue
+			}
+			key := field.Statement + "." + field.Field
+			for _, addendID := range CSVSummaryAddends[key] {
+				addendPrevious, _ := FindCSVValue(rows, addendID, "Prior1Year"+field.Period+suffix)
+				addendCurrent, _ := FindCSVValue(rows, addendID, "CurrentYear"+field.Period+suffix)
+				previous += addendPrevious
+				current += addendCurrent
+			}
+			values[key] = TitleValue{
+				Previous: divideRound(previous, divisor),
+				Current:  divideRound(current, divisor),
+			}
+			break
+		}
+	}
+
+	// 期首残高は前期末の現金及び現金同等物
+	for _, elementID := range []string{"jppfs_cor:CashAndCashEquivalents", "jpigp_cor:CashAndCashEquivalentsIFRS"} {
+		if units["cf"] == "" {
+			break
+		}
+		suffix := ""
+		if !consolidated["cf"] {
+			suffix = "_NonConsolidatedMember"
+		}
+		divisor := UnitDivisor(units["cf"])
+		previous, hasPrevious := FindCSVValue(rows, elementID, "Prior2YearInstant"+suffix)
+		current, hasCurrent := FindCSVValue(rows, elementID, "Prior1YearInstant"+suffix)
+		if hasPrevious || hasCurrent {
+			values["cf.start_cash"] = TitleValue{
+				Previous: divideRound(previous, divisor),
+				Current:  divideRound(current, divisor),
+			}
+			break
+		}
+	}
+	return values
+}
+
+// 円単位の値を単位に合わせて割り、四捨五入する
+func divideRound(value int64, divisor int64) int {
+	quotient, remainder := value/divisor, value%divisor
+	if remainder*2 >= divisor {
+		quotient++
+	} else if remainder*2 <= -divisor {
+		quotient--
+	}
+	return int(quotient)
+}
+
+/*
+HTML から取得した値と CSV の値を比較する
+単位未満の端数処理は企業ごとに異なるため、単位が円以外の場合は 1 以内の差は一致とみなす
+単位が分からない statement は CSV の値を取得しないため比較しない
+*/
+func DiffCSVValues(htmlValues map[string]TitleValue, csvValues map[string]TitleValue, units map[string]string) []CSVDiffItem {
+	var items []CSVDiffItem
+	for _, field := range CSVSummaryFields {
+		if units[field.Statement] == "" {
+			continue
+		}
+		key := field.Statement + "." + field.Field
+		items = appendCSVDiffItem(items, field.Statement, field.Field, htmlValues[key], csvValues, key, units)
+	}
+	if units["cf"] == "" {
+		return items
+	}
+	return appendCSVDiffItem(items, "cf", "start_cash", htmlValues["cf.start_cash"], csvValues, "cf.start_cash", units)
+}
+
+func appendCSVDiffItem(items []CSVDiffItem, statement string, field string, htmlValue TitleValue, csvValues map[string]TitleValue, key string, units map[string]string) []CSVDiffItem {
+	csvValue, found := csvValues[key]
+	tolerance := 0
+	if UnitDivisor(units[statement]) > 1 {
+		tolerance = 1
+	}
+	if found && withinTolerance(htmlValue.Previous, csvValue.Previous, tolerance) && withinTolerance(htmlValue.Current, csvValue.Current, tolerance) {
+		return items
+	}
+	// どちらにも値がない項目は対象外
+	if !found && htmlValue.Previous == 0 && htmlValue.Current == 0 {
+		return items
+	}
+	return append(items, CSVDiffItem{
+		Statement: statement,
+		Field:     field,
+		HTML:      htmlValue,
+		CSV:       csvValue,
+		CSVFound:  found,
+	})
+}
+
+func withinTolerance(a int, b int, tolerance int) bool {
+	diff := a - b
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= tolerance
+}
+
+/*
+CSV (type=5) をダウンロードしてサマリーを上書きし、HTML から取得した値との差分を S3 に登録する
+CSV に値がない項目は HTML から取得した値のまま
+*/
+func ApplyCSVSource(client *http.Client, docID string, dateKey string, EDINETCode string, companyName string, periodStart string, periodEnd string, matches StatementMatches, summary *Summary, plSummary *PLSummary, cfSummary *CFSummary, fundamental *Fundamental, objectKeys []string) {
+	csvZip, err := DownloadDocument(client, docID, 5)
+	if err != nil {
+		ErrMsg = "CSV ダウンロードエラー: "
+		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+		return
+	}
+	rows, err := ReadCSVArchive(csvZip)
+	if err != nil {
+		ErrMsg = "CSV 読み込みエラー: "
+		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+		return
+	}
+
+	consolidated := make(map[string]bool)
+	for statement, fileType := range map[string]string{"bs": "BS", "pl": "PL", "cf": "CF"} {
+		label, _ := SelectStatementMatch(fileType, matches)
+		consolidated[statement] = strings.Contains(label, "連結")
+	}
+	units := map[string]string{
+		"bs": summary.UnitString,
+		"pl": plSummary.UnitString,
+		"cf": cfSummary.UnitString,
+	}
+
+	for _, statement := range []string{"bs", "pl", "cf"} {
+		if units[statement] == "" {
+			fmt.Printf("「%s」のレポート (%s) の %s の単位が分からないため CSV の値で上書きしません❗️\n", companyName, docID, strings.ToUpper(statement))
+		}
+	}
+
+	htmlValues := SummaryTitleValues(summary, plSummary, cfSummary)
+	csvValues := CreateCSVValues(rows, consolidated, units)
+
+	diffItems := DiffCSVValues(htmlValues, csvValues, units)
+	if len(diffItems) == 0 {
+		fmt.Printf("「%s」のレポート (%s) の CSV と HTML の値は一致しています✅\n", companyName, docID)
+	} else {
+		fmt.Printf("「%s」のレポート (%s) の CSV と HTML で %d 件の値が異なります❗️\n", companyName, docID, len(diffItems))
+	}
+
+	csvDiff := CSVDiff{
+		CompanyName: companyName,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		DocID:       docID,
+		Items:       diffItems,
+	}
+	csvDiffJSONBody, err := CreateJSON(docID, dateKey, csvDiff)
+	if err == nil {
+		var csvDiffWg sync.WaitGroup
+		if Parallel == "true" {
+			csvDiffWg.Add(1)
+		}
+		csvDiffFileNamePattern := fmt.Sprintf("%s-%s-CSVDiff-from-%s-to-%s", EDINETCode, docID, periodStart, periodEnd)
+		PutFileToS3(docID, dateKey, EDINETCode, companyName, csvDiffFileNamePattern, "json", csvDiffJSONBody, objectKeys, &csvDiffWg)
+	}
+
+	// CSV の値でサマリーを上書きする
+	setCSVValue := func(key string, target *TitleValue) bool {
+		value, ok := csvValues[key]
+		if ok {
+			*target = value
+		}
+		return ok
+	}
+	for key, target := range summaryTitleValueTargets(summary, plSummary, cfSummary) {
+		setCSVValue(key, target)
+	}
+	if _, ok := csvValues["pl.operating_revenue"]; ok {
+		plSummary.HasOperatingRevenue = true
+	}
+	if _, ok := csvValues["pl.operating_cost"]; ok {
+		plSummary.HasOperatingCost = true
+	}
+
+	// ファンダメンタルズ (当期の値)
+	if fundamental != nil {
+		if value, ok := csvValues["bs.liabilities"]; ok {
+			fundamental.Liabilities = value.Current
+		}
+		if value, ok := csvValues["bs.net_assets"]; ok {
+			fundamental.NetAssets = value.Current
+		}
+		if value, ok := csvValues["pl.sales"]; ok {
+			fundamental.Sales = value.Current
+		}
+		if value, ok := csvValues["pl.operating_profit"]; ok {
+			fundamental.OperatingProfit = value.Current
+		}
+		if value, ok := csvValues["pl.operating_revenue"]; ok {
+			fundamental.OperatingRevenue = value.Current
+			fundamental.HasOperatingRevenue = true
+		}
+		if value, ok := csvValues["pl.operating_cost"]; ok {
+			fundamental.OperatingCost = value.Current
+			fundamental.HasOperatingCost = true
+		}
+		if value, ok := csvValues["pl.net_income_attributable_to_owners"]; ok {
+			fundamental.NetIncome = value.Current
+		} else if value, ok := csvValues["pl.net_income"]; ok {
+			fundamental.NetIncome = value.Current
+		}
+	}
+}
+
+/*
+サマリーの項目を「財務諸表の種類.json フィールド名」(例: pl.sales) をキーとするマップにする
+業種別の勘定科目は「pl.industry_items.勘定科目のキー」をキーとする
+*/
+func SummaryTitleValues(summary *Summary, plSummary *PLSummary, cfSummary *CFSummary) map[string]TitleValue {
+	values := make(map[string]TitleValue)
+	for key, target := range summaryTitleValueTargets(summary, plSummary, cfSummary) {
+		values[key] = *target
+	}
+	for key, value := range plSummary.IndustryItems {
+		values["pl.industry_items."+key] = value
+	}
+	return values
+}
+
+/*
+サマリーの項目のポインタを「財務諸表の種類.json フィールド名」をキーとするマップにする
+CSV の値での上書きと時系列データは同じ項目を扱うため、サマリーに項目を追加した場合はここに追加する
+*/
+func summaryTitleValueTargets(summary *Summary, plSummary *PLSummary, cfSummary *CFSummary) map[string]*TitleValue {
+	return map[string]*TitleValue{
+		"bs.current_assets":                    &summary.CurrentAssets,
+		"bs.tangible_assets":                   &summary.TangibleAssets,
+		"bs.intangible_assets":                 &summary.IntangibleAssets,
+		"bs.investments_and_other_assets":      &summary.InvestmentsAndOtherAssets,
+		"bs.current_liabilities":               &summary.CurrentLiabilities,
+		"bs.fixed_liabilities":                 &summary.FixedLiabilities,
+		"bs.net_assets":                        &summary.NetAssets,
+		"bs.liabilities":                       &summary.Liabilities,
+		"bs.total_assets":                      &summary.TotalAssets,
+		"bs.shareholders_equity":               &summary.ShareholdersEquity,
+		"bs.retained_earnings":                 &summary.RetainedEarnings,
+		"bs.non_controlling_interests":         &summary.NonControllingInterests,
+		"bs.subscription_rights":               &summary.SubscriptionRights,
+		"bs.owners_equity":                     &summary.OwnersEquity,
+		"bs.cash_and_deposits":                 &summary.CashAndDeposits,
+		"bs.short_term_borrowings":             &summary.ShortTermBorrowings,
+		"bs.treasury_stock":                    &summary.TreasuryStock,
+		"bs.inventories":                       &summary.Inventories,
+		"bs.long_term_borrowings":              &summary.LongTermBorrowings,
+		"bs.bonds":                             &summary.Bonds,
+		"bs.bonds_and_borrowings":              &summary.BondsAndBorrowings,
+		"pl.cost_of_goods_sold":                &plSummary.CostOfGoodsSold,
+		"pl.sg_and_a":                          &plSummary.SGAndA,
+		"pl.sales":                             &plSummary.Sales,
+		"pl.operating_profit":                  &plSummary.OperatingProfit,
+		"pl.operating_revenue":                 &plSummary.OperatingRevenue,
+		"pl.operating_cost":                    &plSummary.OperatingCost,
+		"pl.non_operating_income":              &plSummary.NonOperatingIncome,
+		"pl.non_operating_expenses":            &plSummary.NonOperatingExpenses,
+		"pl.ordinary_profit":                   &plSummary.OrdinaryProfit,
+		"pl.extraordinary_income":              &plSummary.ExtraordinaryIncome,
+		"pl.extraordinary_loss":                &plSummary.ExtraordinaryLoss,
+		"pl.profit_before_tax":                 &plSummary.ProfitBeforeTax,
+		"pl.income_taxes":                      &plSummary.IncomeTaxes,
+		"pl.net_income":                        &plSummary.NetIncome,
+		"pl.net_income_attributable_to_owners": &plSummary.NetIncomeAttributableToOwners,
+		"pl.other_comprehensive_income":        &plSummary.OtherComprehensiveIncome,
+		"pl.comprehensive_income":              &plSummary.ComprehensiveIncome,
+		"pl.owners_comprehensive_income":       &plSummary.OwnersComprehensiveIncome,
+		"cf.operating_cf":                      &cfSummary.OperatingCF,
+		"cf.investing_cf":                      &cfSummary.InvestingCF,
+		"cf.financing_cf":                      &cfSummary.FinancingCF,
+		"cf.start_cash":                        &cfSummary.StartCash,
+		"cf.end_cash":                          &cfSummary.EndCash,
+		"cf.net_change":                        &cfSummary.NetChange,
+		"cf.depreciation":                      &cfSummary.Depreciation,
+		"cf.capex":                             &cfSummary.Capex,
+		"cf.dividends_paid":                    &cfSummary.DividendsPaid,
+		"cf.share_buybacks":                    &cfSummary.ShareBuybacks,
+	}
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCreateCSVValues(t *testing.T) {
+	rows := []CSVRow{
+		// IFRS の売上収益 (百万円未満を四捨五入する)
+		{ElementID: "jpigp_cor:RevenueIFRS", ContextID: "Prior1YearDuration", Value: "1234500000"},
+		{ElementID: "jpigp_cor:RevenueIFRS", ContextID: "CurrentYearDuration", Value: "1234499999"},
+		{ElementID: "jpigp_cor:TreasurySharesIFRS", ContextID: "Prior1YearInstant", Value: "-2500000"},
+		{ElementID: "jpigp_cor:TreasurySharesIFRS", ContextID: "CurrentYearInstant", Value: "-2400000"},
+		// 1 年内返済予定の長期借入金を加算する
+		{ElementID: "jppfs_cor:LongTermLoansPayable", ContextID: "Prior1YearInstant", Value: "10000000"},
+		{ElementID: "jppfs_cor:LongTermLoansPayable", ContextID: "CurrentYearInstant", Value: "8000000"},
+		{ElementID: "jppfs_cor:CurrentPortionOfLongTermLoansPayable", ContextID: "Prior1YearInstant", Value: "2000000"},
+		{ElementID: "jppfs_cor:CurrentPortionOfLongTermLoansPayable", ContextID: "CurrentYearInstant", Value: "2000000"},
+		{ElementID: "jppfs_cor:NetCashProvidedByUsedInOperatingActivities", ContextID: "CurrentYearDuration", Value: "3000000"},
+	}
+	consolidated := map[string]bool{"bs": true, "pl": true, "cf": true}
+
+	tests := []struct {
+		name  string
+		units map[string]string
+		want  map[string]TitleValue
+	}{
+		{
+			name:  "百万円単位",
+			units: map[string]string{"bs": "百万円", "pl": "百万円", "cf": "百万円"},
+			want: map[string]TitleValue{
+				"pl.operating_revenue":    {Previous: 1235, Current: 1234},
+				"bs.treasury_stock":       {Previous: -3, Current: -2},
+				"bs.long_term_borrowings": {Previous: 12, Current: 10},
+				"cf.operating_cf":         {Previous: 0, Current: 3},
+			},
+		},
+		{
+			name:  "単位が分からない statement は取得しない",
+			units: map[string]string{"bs": "百万円", "pl": "", "cf": "円"},
+			want: map[string]TitleValue{
+				"bs.treasury_stock":       {Previous: -3, Current: -2},
+				"bs.long_term_borrowings": {Previous: 12, Current: 10},
+				"cf.operating_cf":         {Previous: 0, Current: 3000000},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CreateCSVValues(rows, consolidated, tt.units)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateCSVValues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDivideRound(t *testing.T) {
+	tests := []struct {
+		value   int64
+		divisor int64
+		want    int
+	}{
+		{1499999, 1000000, 1},
+		{1500000, 1000000, 2},
+		{-1500000, 1000000, -2},
+		{-1499999, 1000000, -1},
+		{1234, 1, 1234},
+	}
+	for _, tt := range tests {
+		if got := divideRound(tt.value, tt.divisor); got != tt.want {
+			t.Errorf("divideRound(%d, %d) = %d, want %d", tt.value, tt.divisor, got, tt.want)
+		}
+	}
+}
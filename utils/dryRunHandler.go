@@ -0,0 +1,276 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// "true" の場合 S3, DynamoDB には書き込まず DryRunDir 配下に書き出す
+var DryRun string
+
+// dry-run の出力先ディレクトリ
+var DryRunDir string
+
+// 差分として表示する最大行数
+var dryRunDiffMaxLines = 50
+
+// 差分計算を行う最大の行数 (前後の行数の積)
+var dryRunDiffMaxCells = 4000000
+
+/*
+S3 に送信する代わりに {DryRunDir}/{バケット名}/{キー} に書き出し、S3 上の既存ファイルとの差分を表示する
+*/
+func PutDryRunObject(bucketName string, key string, body []byte) error {
+	var existingBody []byte
+	exists := false
+	output, err := S3Client.GetObject(context.TODO(), &s3.GetObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+	})
+	if err == nil {
+		defer output.Body.Close()
+		existingBody, err = io.ReadAll(output.Body)
+		if err != nil {
+			return err
+		}
+		exists = true
+	}
+
+	path := filepath.Join(DryRunDir, bucketName, key)
+	err = writeDryRunFile(path, body)
+	if err != nil {
+		return err
+	}
+
+	PrintDryRunDiff(fmt.Sprintf("%s/%s", bucketName, key), exists, existingBody, body)
+	return nil
+}
+
+/*
+DynamoDB に書き込む代わりに {DryRunDir}/dynamodb/{テーブル名}/{id}.json に書き出し、既存アイテムとの差分を表示する
+同じ実行内で書き出し済みのアイテムがある場合はその値に上書きし (UpdateSecCode, UpdatePdfKey などの変更を残す)、差分は DynamoDB 上のアイテムとの差分を表示する
+*/
+func PutDryRunItem(dynamoClient *dynamodb.Client, tableName string, id string, item interface{}) error {
+	newBody, err := writeDryRunItem(tableName, id, item)
+	if err != nil {
+		return err
+	}
+
+	var existingBody []byte
+	exists := false
+	output, err := dynamoClient.GetItem(context.TODO(), &dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err == nil && len(output.Item) > 0 {
+		existingBody, err = marshalDynamoItem(output.Item)
+		if err != nil {
+			return err
+		}
+		exists = true
+	}
+
+	PrintDryRunDiff(fmt.Sprintf("dynamodb/%s/%s", tableName, id), exists, existingBody, newBody)
+	return nil
+}
+
+// 書き出し済みのアイテムに item の属性を上書きして書き出し、書き出した JSON を返す
+func writeDryRunItem(tableName string, id string, item interface{}) ([]byte, error) {
+	newItem, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return nil, err
+	}
+	localItem, _, err := GetDryRunItem(tableName, id)
+	if err != nil {
+		return nil, err
+	}
+	body, err := marshalDynamoItem(mergeDynamoItems(localItem, newItem))
+	if err != nil {
+		return nil, err
+	}
+	return body, writeDryRunFile(dryRunItemPath(tableName, id), body)
+}
+
+/*
+書き出し済みの DynamoDB のアイテムを取得する
+書き出していない場合は false を返す
+*/
+func GetDryRunItem(tableName string, id string) (map[string]types.AttributeValue, bool, error) {
+	body, err := os.ReadFile(dryRunItemPath(tableName, id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var m map[string]interface{}
+	err = json.Unmarshal(body, &m)
+	if err != nil {
+		return nil, false, err
+	}
+	item, err := attributevalue.MarshalMap(m)
+	if err != nil {
+		return nil, false, err
+	}
+	return item, true, nil
+}
+
+/*
+書き出し済みの DynamoDB のアイテムから、属性 (例: edinetCode) の値が一致するものを取得する
+DynamoDB の GSI の Query の代わりに使う
+*/
+func QueryDryRunItems(tableName string, attributeName string, value string) ([]map[string]types.AttributeValue, error) {
+	entries, err := os.ReadDir(filepath.Join(DryRunDir, "dynamodb", tableName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var items []map[string]types.AttributeValue
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		item, found, err := GetDryRunItem(tableName, strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		attribute, ok := item[attributeName].(*types.AttributeValueMemberS)
+		if found && ok && attribute.Value == value {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func dryRunItemPath(tableName string, id string) string {
+	return filepath.Join(DryRunDir, "dynamodb", tableName, fmt.Sprintf("%s.json", id))
+}
+
+// base の属性を update の属性で上書きしたアイテムを返す
+func mergeDynamoItems(base map[string]types.AttributeValue, update map[string]types.AttributeValue) map[string]types.AttributeValue {
+	merged := make(map[string]types.AttributeValue, len(base)+len(update))
+	for name, value := range base {
+		merged[name] = value
+	}
+	for name, value := range update {
+		merged[name] = value
+	}
+	return merged
+}
+
+/*
+S3 から削除する代わりに削除予定であることを表示する
+*/
+func DeleteDryRunObject(bucketName string, key string) {
+	fmt.Printf("[dry-run] %s/%s を削除します (実際には削除しません)\n", bucketName, key)
+}
+
+// DynamoDB のアイテムを比較しやすい JSON に変換する (キーはソートされる)
+func marshalDynamoItem(item map[string]types.AttributeValue) ([]byte, error) {
+	var m map[string]interface{}
+	err := attributevalue.UnmarshalMap(item, &m)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(m, "", "  ")
+}
+
+func writeDryRunFile(path string, body []byte) error {
+	err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, body, 0666)
+}
+
+/*
+既存データとの差分を行単位で表示する
+*/
+func PrintDryRunDiff(label string, exists bool, before []byte, after []byte) {
+	if !exists {
+		fmt.Printf("[dry-run] %s を新規作成します (%d bytes)\n", label, len(after))
+		return
+	}
+	if string(before) == string(after) {
+		fmt.Printf("[dry-run] %s は変更ありません\n", label)
+		return
+	}
+
+	fmt.Printf("[dry-run] %s を更新します (%d bytes → %d bytes)\n", label, len(before), len(after))
+	beforeLines := strings.Split(string(before), "\n")
+	afterLines := strings.Split(string(after), "\n")
+	if len(beforeLines)*len(afterLines) > dryRunDiffMaxCells {
+		fmt.Println("  行数が多いため差分の表示を省略します")
+		return
+	}
+
+	diffLines := diffLines(beforeLines, afterLines)
+	for i, line := range diffLines {
+		if i >= dryRunDiffMaxLines {
+			fmt.Printf("  ... 他 %d 行\n", len(diffLines)-dryRunDiffMaxLines)
+			break
+		}
+		fmt.Println("  " + line)
+	}
+}
+
+/*
+最長共通部分列から削除行 (-) と追加行 (+) を求める
+*/
+func diffLines(before []string, after []string) []string {
+	// lcs[i][j]: before[i:] と after[j:] の最長共通部分列の長さ
+	lcs := make([][]int, len(before)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var result []string
+	i, j := 0, 0
+	for i < len(before) && j < len(after) {
+		if before[i] == after[j] {
+			i++
+			j++
+		} else if lcs[i+1][j] >= lcs[i][j+1] {
+			result = append(result, "- "+before[i])
+			i++
+		} else {
+			result = append(result, "+ "+after[j])
+			j++
+		}
+	}
+	for ; i < len(before); i++ {
+		result = append(result, "- "+before[i])
+	}
+	for ; j < len(after); j++ {
+		result = append(result, "+ "+after[j])
+	}
+	return result
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+)
+
+// 書き出し済みの企業情報に証券コード・PDF キーの変更を順に反映する
+func TestDryRunItemMerge(t *testing.T) {
+	DryRunDir = t.TempDir()
+	tableName := "compass_companies"
+	updatedAt := time.Date(2024, 6, 30, 9, 0, 0, 0, time.UTC)
+
+	company := Company{ID: "id-1", EDINETCode: "E00001", Name: "テスト株式会社", BS: 1}
+	writeTestDryRunItem(t, tableName, company)
+
+	// 別の企業
+	writeTestDryRunItem(t, tableName, Company{ID: "id-2", EDINETCode: "E00002", Name: "別の株式会社"})
+
+	items, err := QueryDryRunItems(tableName, "edinetCode", "E00001")
+	if err != nil {
+		t.Fatalf("QueryDryRunItems error: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("QueryDryRunItems returned %d items, want 1", len(items))
+	}
+
+	// UpdateSecCode 相当の変更
+	var found Company
+	if err := attributevalue.UnmarshalMap(items[0], &found); err != nil {
+		t.Fatalf("UnmarshalMap error: %v", err)
+	}
+	found.SecurityCode = "1234"
+	found.UpdatedAt = updatedAt
+	writeTestDryRunItem(t, tableName, found)
+
+	// UpdatePdfKey 相当の変更 (取得し直した企業情報に証券コードが残っている)
+	item, ok, err := GetDryRunItem(tableName, "id-1")
+	if err != nil || !ok {
+		t.Fatalf("GetDryRunItem = (%v, %v)", ok, err)
+	}
+	var got Company
+	if err := attributevalue.UnmarshalMap(item, &got); err != nil {
+		t.Fatalf("UnmarshalMap error: %v", err)
+	}
+	want := company
+	want.SecurityCode = "1234"
+	want.UpdatedAt = updatedAt
+	if got.ID != want.ID || got.SecurityCode != want.SecurityCode || got.BS != want.BS || !got.UpdatedAt.Equal(want.UpdatedAt) {
+		t.Errorf("GetDryRunItem = %+v, want %+v", got, want)
+	}
+
+	if _, ok, err := GetDryRunItem(tableName, "id-3"); ok || err != nil {
+		t.Errorf("GetDryRunItem(id-3) = (%v, %v), want (false, nil)", ok, err)
+	}
+}
+
+func TestMergeDynamoItems(t *testing.T) {
+	base, _ := attributevalue.MarshalMap(map[string]interface{}{"id": "id-1", "securityCode": "1234", "pdfKey": ""})
+	update, _ := attributevalue.MarshalMap(map[string]interface{}{"id": "id-1", "pdfKey": "20240630/S100TEST/S100TEST.pdf"})
+	var got map[string]string
+	if err := attributevalue.UnmarshalMap(mergeDynamoItems(base, update), &got); err != nil {
+		t.Fatalf("UnmarshalMap error: %v", err)
+	}
+	if got["securityCode"] != "1234" || got["pdfKey"] != "20240630/S100TEST/S100TEST.pdf" {
+		t.Errorf("mergeDynamoItems = %v", got)
+	}
+}
+
+func writeTestDryRunItem(t *testing.T, tableName string, company Company) {
+	t.Helper()
+	if _, err := writeDryRunItem(tableName, company.ID, company); err != nil {
+		t.Fatal(err)
+	}
+}
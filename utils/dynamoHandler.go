@@ -3,6 +3,7 @@ package utils
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
 	"time"
 
@@ -12,33 +13,52 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
+/*
+EDINET コードで企業情報を取得する (edinetCode-index)
+dry-run の場合は同じ実行内で書き出した企業情報 (RegisterCompany で新規登録した企業など) を優先する
+*/
+func QueryCompaniesByEDINETCode(dynamoClient *dynamodb.Client, tableName string, EDINETCode string) ([]map[string]types.AttributeValue, error) {
+	if DryRun == "true" {
+		items, err := QueryDryRunItems(TableName, "edinetCode", EDINETCode)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) > 0 {
+			return items, nil
+		}
+	}
+	queryInput := &dynamodb.QueryInput{
+		TableName:              aws.String(tableName),
+		IndexName:              aws.String("edinetCode-index"),
+		KeyConditionExpression: aws.String("#k = :v"),
+		ExpressionAttributeNames: map[string]string{
+			"#k": "edinetCode",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":v": &types.AttributeValueMemberS{Value: EDINETCode},
+		},
+	}
+	queryOutput, err := dynamoClient.Query(context.TODO(), queryInput)
+	if err != nil {
+		return nil, err
+	}
+	return queryOutput.Items, nil
+}
+
 func UpdateSecCode(dynamoClient *dynamodb.Client, EDINETCode string, securityCode string) error {
 	fmt.Printf("証券コードパラメータ: %s ⚾️\n", securityCode)
 	trimmedSecCode := strings.TrimSpace(securityCode)
 	if trimmedSecCode != "" {
-		queryInput := &dynamodb.QueryInput{
-			TableName: aws.String("compass_companies"),
-			IndexName: aws.String("edinetCode-index"),
-			KeyConditionExpression: aws.String("#k = :v"),
-			ExpressionAttributeNames: map[string]string{
-				"#k": "edinetCode",
-			},
-			ExpressionAttributeValues: map[string]types.AttributeValue{
-				":v": &types.AttributeValueMemberS{Value: EDINETCode},
-			},
-		}
 		// クエリを実行
-		queryOutput, err := dynamoClient.Query(context.TODO(), queryInput)
+		companyItems, err := QueryCompaniesByEDINETCode(dynamoClient, "compass_companies", EDINETCode)
 		if err != nil {
 			fmt.Println("query error: ", err)
 			return err
 		}
 
-		if len(queryOutput.Items) > 0 {
+		if len(companyItems) > 0 {
 			var company Company
-			// primaryKey := queryOutput.Items[0]["PrimaryKeyAttributeName"].(*types.AttributeValueMemberS).Value
-			// fmt.Println("プライマリキー: ", primaryKey)
-			item := queryOutput.Items[0]
+			item := companyItems[0]
 			err := attributevalue.UnmarshalMap(item, &company)
 			if err != nil {
 				fmt.Println("MarshalMap err: ", err)
@@ -51,6 +71,11 @@ func UpdateSecCode(dynamoClient *dynamodb.Client, EDINETCode string, securityCod
 				if err != nil {
 					return err
 				}
+				if DryRun == "true" {
+					company.SecurityCode = securityCode
+					company.UpdatedAt = time.Now().In(loc)
+					return PutDryRunItem(dynamoClient, TableName, company.ID, company)
+				}
 				// 更新するカラムとその値の指定
 				updateInput := &dynamodb.UpdateItemInput{
 					TableName: aws.String(TableName),
@@ -59,11 +84,11 @@ func UpdateSecCode(dynamoClient *dynamodb.Client, EDINETCode string, securityCod
 					},
 					UpdateExpression: aws.String("SET #secCode = :secCode, #updatedAt = :updatedAt"),
 					ExpressionAttributeNames: map[string]string{
-						"#secCode": "securityCode", // "securityCode" カラムを指定
+						"#secCode":   "securityCode", // "securityCode" カラムを指定
 						"#updatedAt": "updatedAt",    // "updateAt" カラムを指定
 					},
 					ExpressionAttributeValues: map[string]types.AttributeValue{
-						":secCode": &types.AttributeValueMemberS{Value: securityCode},
+						":secCode":   &types.AttributeValueMemberS{Value: securityCode},
 						":updatedAt": &types.AttributeValueMemberS{Value: time.Now().In(loc).Format(time.RFC3339)}, // 現在の日時を設定
 					},
 					ReturnValues: types.ReturnValueUpdatedNew, // 更新後の新しい値を返す
@@ -80,4 +105,140 @@ func UpdateSecCode(dynamoClient *dynamodb.Client, EDINETCode string, securityCod
 		}
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+/*
+企業情報に PDF (有価証券報告書の原本) のキーを設定する
+*/
+func UpdatePdfKey(dynamoClient *dynamodb.Client, EDINETCode string, pdfKey string) error {
+	companyItems, err := QueryCompaniesByEDINETCode(dynamoClient, TableName, EDINETCode)
+	if err != nil {
+		return err
+	}
+	if len(companyItems) == 0 {
+		fmt.Printf("EDINET コード %s の企業が登録されていないため PDF キーを設定できません❗️\n", EDINETCode)
+		return nil
+	}
+
+	var company Company
+	err = attributevalue.UnmarshalMap(companyItems[0], &company)
+	if err != nil {
+		return err
+	}
+	if company.PdfKey == pdfKey {
+		return nil
+	}
+
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		return err
+	}
+	if DryRun == "true" {
+		company.PdfKey = pdfKey
+		company.UpdatedAt = time.Now().In(loc)
+		return PutDryRunItem(dynamoClient, TableName, company.ID, company)
+	}
+
+	updateInput := &dynamodb.UpdateItemInput{
+		TableName: aws.String(TableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: company.ID},
+		},
+		UpdateExpression: aws.String("SET #pdfKey = :pdfKey, #updatedAt = :updatedAt"),
+		ExpressionAttributeNames: map[string]string{
+			"#pdfKey":    "pdfKey",
+			"#updatedAt": "updatedAt",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pdfKey":    &types.AttributeValueMemberS{Value: pdfKey},
+			":updatedAt": &types.AttributeValueMemberS{Value: time.Now().In(loc).Format(time.RFC3339)},
+		},
+		ReturnValues: types.ReturnValueUpdatedNew,
+	}
+	_, err = dynamoClient.UpdateItem(context.TODO(), updateInput)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("「%s」の PDF キー (%s) をDBに設定しました📄\n", company.Name, pdfKey)
+	return nil
+}
+
+/*
+企業情報に DEI・企業の概況から取得した情報を設定する
+*/
+func UpdateCompanyOverview(dynamoClient *dynamodb.Client, EDINETCode string, overview CompanyOverview) error {
+	if overview == (CompanyOverview{}) {
+		return nil
+	}
+	companyItems, err := QueryCompaniesByEDINETCode(dynamoClient, TableName, EDINETCode)
+	if err != nil {
+		return err
+	}
+	if len(companyItems) == 0 {
+		fmt.Printf("EDINET コード %s の企業が登録されていないため企業の概況を設定できません❗️\n", EDINETCode)
+		return nil
+	}
+
+	var company Company
+	err = attributevalue.UnmarshalMap(companyItems[0], &company)
+	if err != nil {
+		return err
+	}
+	merged := MergeCompanyOverview(company.CompanyOverview, overview)
+	if merged == company.CompanyOverview {
+		return nil
+	}
+
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		return err
+	}
+	if DryRun == "true" {
+		company.CompanyOverview = merged
+		company.UpdatedAt = time.Now().In(loc)
+		return PutDryRunItem(dynamoClient, TableName, company.ID, company)
+	}
+
+	overviewItem, err := attributevalue.MarshalMap(merged)
+	if err != nil {
+		return err
+	}
+	currentItem, err := attributevalue.MarshalMap(company.CompanyOverview)
+	if err != nil {
+		return err
+	}
+	var setExpressions []string
+	attributeNames := map[string]string{
+		"#updatedAt": "updatedAt",
+	}
+	attributeValues := map[string]types.AttributeValue{
+		":updatedAt": &types.AttributeValueMemberS{Value: time.Now().In(loc).Format(time.RFC3339)},
+	}
+	// 値が変わった項目のみ SET する (今回の書類で取得できなかった項目は登録済みの値のまま)
+	for name, value := range overviewItem {
+		if reflect.DeepEqual(value, currentItem[name]) {
+			continue
+		}
+		setExpressions = append(setExpressions, fmt.Sprintf("#%s = :%s", name, name))
+		attributeNames["#"+name] = name
+		attributeValues[":"+name] = value
+	}
+	setExpressions = append(setExpressions, "#updatedAt = :updatedAt")
+
+	updateInput := &dynamodb.UpdateItemInput{
+		TableName: aws.String(TableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: company.ID},
+		},
+		UpdateExpression:          aws.String("SET " + strings.Join(setExpressions, ", ")),
+		ExpressionAttributeNames:  attributeNames,
+		ExpressionAttributeValues: attributeValues,
+		ReturnValues:              types.ReturnValueUpdatedNew,
+	}
+	_, err = dynamoClient.UpdateItem(context.TODO(), updateInput)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("「%s」の企業の概況をDBに設定しました🏢\n", company.Name)
+	return nil
+}
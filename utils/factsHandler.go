@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+)
+
+// XBRL インスタンスで使う名前空間
+const (
+	XBRLINamespace = "http://www.xbrl.org/2003/instance"
+	LinkNamespace  = "http://www.xbrl.org/2003/linkbase"
+	XSINamespace   = "http://www.w3.org/2001/XMLSchema-instance"
+)
+
+/*
+XBRL インスタンスの直下にある事実 (context, unit, schemaRef 以外の要素) をすべて取得する
+要素名は「接頭辞:要素名」(例: jppfs_cor:NetSales) の形式にする
+*/
+func ParseInstanceFacts(body []byte) ([]Fact, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	// 名前空間 URI → 接頭辞
+	prefixes := make(map[string]string)
+	var facts []Fact
+	depth := 0
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				return facts, nil
+			}
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 1 {
+				for _, attr := range t.Attr {
+					if attr.Name.Space == "xmlns" {
+						prefixes[attr.Value] = attr.Name.Local
+					}
+				}
+				continue
+			}
+			if depth != 2 || t.Name.Space == XBRLINamespace || t.Name.Space == LinkNamespace {
+				continue
+			}
+			// 要素で宣言されている名前空間も接頭辞に使う
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" {
+					prefixes[attr.Value] = attr.Name.Local
+				}
+			}
+
+			var raw struct {
+				ContextRef string `xml:"contextRef,attr"`
+				UnitRef    string `xml:"unitRef,attr"`
+				Decimals   string `xml:"decimals,attr"`
+				Nil        string `xml:"http://www.w3.org/2001/XMLSchema-instance nil,attr"`
+				Value      string `xml:",chardata"`
+			}
+			err := decoder.DecodeElement(&raw, &t)
+			if err != nil {
+				return nil, err
+			}
+			// DecodeElement で終了タグまで読み込むので depth を戻す
+			depth--
+
+			facts = append(facts, Fact{
+				Name:       qualifiedName(prefixes, t.Name),
+				ContextRef: raw.ContextRef,
+				UnitRef:    raw.UnitRef,
+				Decimals:   raw.Decimals,
+				Value:      strings.TrimSpace(raw.Value),
+				IsNil:      raw.Nil == "true",
+			})
+		case xml.EndElement:
+			depth--
+		}
+	}
+}
+
+// 名前空間 URI を接頭辞に置き換えた要素名
+func qualifiedName(prefixes map[string]string, name xml.Name) string {
+	prefix, ok := prefixes[name.Space]
+	if !ok || prefix == "" {
+		return name.Local
+	}
+	return fmt.Sprintf("%s:%s", prefix, name.Local)
+}
+
+/*
+要素名とコンテキストで事実を探す
+*/
+func FindFact(facts []Fact, name string, contextRef string) (Fact, bool) {
+	for _, fact := range facts {
+		if fact.Name == name && fact.ContextRef == contextRef {
+			return fact, true
+		}
+	}
+	return Fact{}, false
+}
+
+/*
+2 つの事実一覧で値が異なる数値の事実を返す
+要素名とコンテキストが一致するものだけを比較する
+*/
+func DiffFacts(base []Fact, target []Fact) []FactDiff {
+	targetValues := make(map[string]Fact)
+	for _, fact := range target {
+		if fact.UnitRef == "" {
+			continue
+		}
+		targetValues[fact.Name+"@"+fact.ContextRef] = fact
+	}
+
+	var diffs []FactDiff
+	for _, fact := range base {
+		if fact.UnitRef == "" {
+			continue
+		}
+		targetFact, ok := targetValues[fact.Name+"@"+fact.ContextRef]
+		if !ok || equalNumericValue(fact.Value, targetFact.Value) {
+			continue
+		}
+		diffs = append(diffs, FactDiff{
+			Name:        fact.Name,
+			ContextRef:  fact.ContextRef,
+			BaseValue:   fact.Value,
+			TargetValue: targetFact.Value,
+		})
+	}
+	return diffs
+}
+
+// 数値として等しいかどうか (1000 と 1000.0 などを同じとみなす)
+func equalNumericValue(a string, b string) bool {
+	aRat, aOk := new(big.Rat).SetString(a)
+	bRat, bOk := new(big.Rat).SetString(b)
+	if !aOk || !bOk {
+		return a == b
+	}
+	return aRat.Cmp(bRat) == 0
+}
@@ -0,0 +1,287 @@
+package utils
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// "true" の場合、投資信託の有価証券報告書 (内国投資信託受益証券) も登録する
+var FundMode string
+
+// 有価証券報告書（内国投資信託受益証券）の様式コード
+const (
+	FundSecReportFormCode   = "07A000" // 有価証券報告書
+	FundAmendReportFormCode = "07A001" // 訂正有価証券報告書
+)
+
+// 投資信託のファイルを登録する S3 のプレフィックス (EDINET コードの代わりに使う)
+const FundKeyPrefix = "Funds"
+
+// 純資産・1口当たり純資産額の当期・前期のコンテキスト (計算期間が 1 年未満のものもあるため Period も探す)
+var (
+	FundCurrentInstantContexts = []string{"CurrentYearInstant", "CurrentPeriodInstant", "CurrentYearInstant_NonConsolidatedMember", "CurrentPeriodInstant_NonConsolidatedMember"}
+	FundPriorInstantContexts   = []string{"Prior1YearInstant", "Prior1PeriodInstant", "Prior1YearInstant_NonConsolidatedMember", "Prior1PeriodInstant_NonConsolidatedMember"}
+)
+
+// 例: 1口当たり純資産額 1.2345円, 1万口当たり純資産額 12,345円 (注記の表では前期・当期の値が続く)
+var NAVPerUnitRe = regexp.MustCompile(`([0-9,]*)(万)?口当たり純資産額[^0-9]{0,30}?((?:[0-9,]+(?:\.[0-9]+)?円)+)`)
+
+// 例: 期末における受益権の総数 123,456,789口
+var TotalUnitsRe = regexp.MustCompile(`受益権の?総(?:口)?数[^0-9]{0,30}?((?:[0-9,]+口)+)`)
+
+// 続けて記載された値を 1 つずつ取得する
+var (
+	yenValueRe  = regexp.MustCompile(`([0-9,]+(?:\.[0-9]+)?)円`)
+	unitValueRe = regexp.MustCompile(`([0-9,]+)口`)
+)
+
+var fullWidthNumReplacer = strings.NewReplacer(
+	"０", "0", "１", "1", "２", "2", "３", "3", "４", "4",
+	"５", "5", "６", "6", "７", "7", "８", "8", "９", "9",
+	"，", ",", "．", ".",
+)
+
+/*
+投資信託の有価証券報告書かどうか
+*/
+func IsFundReport(report Result) bool {
+	// 有価証券報告書 (Securities Report)
+	isSecReport := report.FormCode == FundSecReportFormCode && report.DocTypeCode == "120"
+	// 訂正有価証券報告書 (Amended Securities Report)
+	isAmendReport := report.FormCode == FundAmendReportFormCode && report.DocTypeCode == "130"
+	return report.FundCode != "" && (isSecReport || isAmendReport)
+}
+
+/*
+投資信託の有価証券報告書を登録する
+純資産総額・1口当たり純資産額・信託報酬などを Funds/{ファンドコード}/Fund/ 配下に登録する
+*/
+func RegisterFundReport(report Result, periodStart string, periodEnd string, wg *sync.WaitGroup) {
+	fundCode := report.FundCode
+	docID := report.DocId
+	dateKey := report.DateKey
+	fundName := report.FilerName
+	// 並列で処理する場合
+	if Parallel == "true" {
+		defer wg.Done()
+	}
+
+	fundKey := fmt.Sprintf("%s/%s", FundKeyPrefix, fundCode)
+	var objectKeys []string
+	listObjectsOutput := ListS3Objects(S3Client, BucketName, fundKey)
+	for _, item := range listObjectsOutput.Contents {
+		objectKeys = append(objectKeys, *item.Key)
+	}
+
+	client := &http.Client{
+		Timeout: 300 * time.Second,
+	}
+	fmt.Printf("投資信託「%s」のレポート (%s) を API から取得します🎾\n", fundCode, docID)
+	ApiTimes += 1
+	zipBody, err := DownloadDocument(client, docID, 1)
+	if err != nil {
+		ErrMsg = "投資信託の http get error : "
+		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+		return
+	}
+	archive, err := Unzip(zipBody)
+	if err != nil {
+		ErrMsg = "投資信託の Error unzipping file: "
+		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+		return
+	}
+	body := archive.Main.Body
+
+	// オリジナルHTMLを S3 に送信
+	splitBySlash := strings.Split(archive.Main.Path, "/")
+	xbrlKey := fmt.Sprintf("%s/%s/%s", dateKey, docID, splitBySlash[len(splitBySlash)-1])
+	PutOriginalHTMLToS3(docID, dateKey, xbrlKey, string(body))
+
+	fundSummary, err := CreateFundSummaryFromInstance(fundCode, fundName, periodStart, periodEnd, body)
+	if err != nil {
+		ErrMsg = "投資信託の XBRL Unmarshal err: "
+		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+		return
+	}
+	fundName = fundSummary.FundName
+	if !ValidateFundSummary(fundSummary) {
+		PrintValidatedSummaryMsg(fundName, "Fund", fundSummary, false)
+		RegisterInvalidSummaryJson(docID, dateKey, "Fund", fundName)
+		return
+	}
+
+	var fundWg sync.WaitGroup
+	if Parallel == "true" {
+		fundWg.Add(1)
+	}
+	fundFileNamePattern := fmt.Sprintf("%s-%s-Fund-from-%s-to-%s", fundCode, docID, periodStart, periodEnd)
+	HandleRegisterJSON(docID, dateKey, fundKey, fundName, fundFileNamePattern, fundSummary, objectKeys, &fundWg)
+
+	deleteInvalidSummaryJsonItem(docID, dateKey, "Fund", fundName)
+	deleteFailedJsonItem(docID, dateKey, fundName)
+	fmt.Printf("投資信託「%s」のレポート(%s)の登録処理完了⭐️\n", fundName, docID)
+}
+
+/*
+投資信託の XBRL インスタンス (jpsps) からサマリーを作成する
+ファンド名は DEI から取得し、ない場合は fundName (提出者名) を使う
+*/
+func CreateFundSummaryFromInstance(fundCode string, fundName string, periodStart string, periodEnd string, body []byte) (FundSummary, error) {
+	var xbrl XBRL
+	err := xml.Unmarshal(body, &xbrl)
+	if err != nil {
+		return FundSummary{}, err
+	}
+	facts, err := ParseInstanceFacts(body)
+	if err != nil {
+		fmt.Println("ParseInstanceFacts error: ", err)
+	}
+
+	if name := FindFactValue(facts, "jpdei_cor:FundNameInJapaneseDEI", "FilingDateInstant"); name != "" {
+		fundName = name
+	}
+	return CreateFundSummary(fundCode, fundName, periodStart, periodEnd, xbrl, facts), nil
+}
+
+/*
+投資信託のサマリーを作成する
+純資産は XBRL の事実を優先し、ない場合は貸借対照表から取得する
+1口当たり純資産額・受益権の総数は注記、信託報酬は損益及び剰余金計算書から取得する
+*/
+func CreateFundSummary(fundCode string, fundName string, periodStart string, periodEnd string, xbrl XBRL, facts []Fact) FundSummary {
+	summary := FundSummary{
+		FundCode:    fundCode,
+		FundName:    fundName,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+	}
+
+	summary.NetAssets.Current, _ = strconv.Atoi(FindFactValue(facts, "jppfs_cor:NetAssets", FundCurrentInstantContexts...))
+	summary.NetAssets.Previous, _ = strconv.Atoi(FindFactValue(facts, "jppfs_cor:NetAssets", FundPriorInstantContexts...))
+	if summary.NetAssets.Current == 0 {
+		summary.NetAssets = FindFundStatementValue(facts, "純資産合計", "純資産の部合計")
+	}
+	summary.TrustFees = FindFundStatementValue(facts, "信託報酬")
+	summary.OperatingExpense = FindFundStatementValue(facts, "営業費用合計")
+	summary.MoneyHeldInTrust, _ = ConvertTextValue2IntValue(xbrl.MoneyHeldInTrust.Value)
+
+	for _, fact := range facts {
+		if !strings.HasSuffix(fact.Name, "TextBlock") {
+			continue
+		}
+		text := fullWidthNumReplacer.Replace(removeSpaces(TagRe.ReplaceAllString(html.UnescapeString(fact.Value), "")))
+		if summary.NAVPerUnit == (FloatTitleValue{}) {
+			summary.NAVPerUnit, summary.NAVUnitCount = ParseNAVPerUnit(text)
+		}
+		if summary.TotalUnits == (TitleValue{}) {
+			summary.TotalUnits = ParseTotalUnits(text)
+		}
+	}
+	return summary
+}
+
+/*
+注記の文から 1口当たり純資産額 (前期・当期) と口数単位を取得する
+2 つ以上ある場合は最初を前期、2 つ目を当期とする
+*/
+func ParseNAVPerUnit(text string) (FloatTitleValue, int) {
+	var nav FloatTitleValue
+	unitCount := 0
+	matches := NAVPerUnitRe.FindAllStringSubmatch(text, -1)
+	var values []float64
+	for _, match := range matches {
+		if unitCount == 0 {
+			unitCount = 1
+			if count, err := strconv.Atoi(strings.ReplaceAll(match[1], ",", "")); err == nil && count > 0 {
+				unitCount = count
+			}
+			if match[2] == "万" {
+				unitCount *= 10000
+			}
+		}
+		for _, valueMatch := range yenValueRe.FindAllStringSubmatch(match[3], -1) {
+			value, err := strconv.ParseFloat(strings.ReplaceAll(valueMatch[1], ",", ""), 64)
+			if err == nil {
+				values = append(values, value)
+			}
+		}
+	}
+	switch {
+	case len(values) >= 2:
+		nav.Previous, nav.Current = values[0], values[1]
+	case len(values) == 1:
+		nav.Current = values[0]
+	}
+	return nav, unitCount
+}
+
+/*
+注記の文から受益権の総数 (前期・当期) を取得する
+*/
+func ParseTotalUnits(text string) TitleValue {
+	var units TitleValue
+	var values []int
+	for _, match := range TotalUnitsRe.FindAllStringSubmatch(text, -1) {
+		for _, valueMatch := range unitValueRe.FindAllStringSubmatch(match[1], -1) {
+			value, err := strconv.Atoi(strings.ReplaceAll(valueMatch[1], ",", ""))
+			if err == nil {
+				values = append(values, value)
+			}
+		}
+	}
+	switch {
+	case len(values) >= 2:
+		units.Previous, units.Current = values[0], values[1]
+	case len(values) == 1:
+		units.Current = values[0]
+	}
+	return units
+}
+
+/*
+財務諸表の TextBlock から勘定科目の値 (前期・当期) を取得する
+単位が千円の場合は円に換算する
+*/
+func FindFundStatementValue(facts []Fact, titles ...string) TitleValue {
+	for _, fact := range facts {
+		if !strings.HasSuffix(fact.Name, "TextBlock") {
+			continue
+		}
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(html.UnescapeString(fact.Value)))
+		if err != nil {
+			continue
+		}
+		multiplier := 1
+		if strings.Contains(doc.Text(), "単位：千円") {
+			multiplier = 1000
+		}
+		for _, row := range statementRows(doc) {
+			for _, title := range titles {
+				if strings.HasPrefix(row.titleName, title) {
+					return TitleValue{
+						Previous: row.titleValue.Previous * multiplier,
+						Current:  row.titleValue.Current * multiplier,
+					}
+				}
+			}
+		}
+	}
+	return TitleValue{}
+}
+
+func ValidateFundSummary(summary FundSummary) bool {
+	return summary.FundCode != "" &&
+		summary.PeriodStart != "" &&
+		summary.PeriodEnd != "" &&
+		summary.NetAssets.Current != 0 &&
+		summary.NAVPerUnit.Current != 0
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"html"
+	"testing"
+)
+
+// 内国投資信託受益証券の有価証券報告書 (jpsps) の XBRL インスタンス
+func fundTestInstance() string {
+	notes := html.EscapeString(`<p>1口当たり純資産額</p><p>1.0234円</p><p>1.2345円</p>` +
+		`<p>期末における受益権の総数</p><p>9,771,234,567口</p><p>10,000,000,000口</p>`)
+	statement := html.EscapeString(`<table>
+<tr><td>
+<p>（単位：円）</p>
+</td></tr>
+<tr><td>
+<p>信託報酬</p>
+</td><td>
+<p>△12,345</p>
+</td><td>
+<p>△23,456</p>
+</td></tr>
+<tr><td>
+<p>営業費用合計</p>
+</td><td>
+<p>15,000</p>
+</td><td>
+<p>26,000</p>
+</td></tr>
+</table>`)
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<xbrli:xbrl xmlns:xbrli="http://www.xbrl.org/2003/instance" xmlns:link="http://www.xbrl.org/2003/linkbase" xmlns:xlink="http://www.w3.org/1999/xlink"
+  xmlns:jpdei_cor="http://disclosure.edinet-fsa.go.jp/taxonomy/jpdei/2013-08-31/jpdei_cor"
+  xmlns:jppfs_cor="http://disclosure.edinet-fsa.go.jp/taxonomy/jppfs/2023-12-01/jppfs_cor"
+  xmlns:jpsps_cor="http://disclosure.edinet-fsa.go.jp/taxonomy/jpsps/2023-12-01/jpsps_cor"
+  xmlns:jpsps070000-asr_G00001-000="http://disclosure.edinet-fsa.go.jp/jpsps070000/asr/001/G00001-000/2024-01-15/01/2024-04-10">
+<link:schemaRef xlink:type="simple" xlink:href="jpsps070000-asr-001_G00001-000_2024-01-15_01_2024-04-10.xsd"/>
+<jpdei_cor:FundNameInJapaneseDEI contextRef="FilingDateInstant">テスト・インデックス・ファンド</jpdei_cor:FundNameInJapaneseDEI>
+<jppfs_cor:NetAssets contextRef="CurrentPeriodInstant_NonConsolidatedMember" unitRef="JPY" decimals="0">12345678900</jppfs_cor:NetAssets>
+<jppfs_cor:NetAssets contextRef="Prior1PeriodInstant_NonConsolidatedMember" unitRef="JPY" decimals="0">10000000000</jppfs_cor:NetAssets>
+<jppfs_cor:MoneyHeldInTrustCAFND contextRef="CurrentPeriodInstant_NonConsolidatedMember" unitRef="JPY" decimals="0">500000</jppfs_cor:MoneyHeldInTrustCAFND>
+<jpsps_cor:NotesToFinancialStatementsTextBlock contextRef="CurrentPeriodDuration">` + notes + `</jpsps_cor:NotesToFinancialStatementsTextBlock>
+<jpsps_cor:StatementOfIncomeAndRetainedEarningsTextBlock contextRef="CurrentPeriodDuration">` + statement + `</jpsps_cor:StatementOfIncomeAndRetainedEarningsTextBlock>
+</xbrli:xbrl>`
+}
+
+func TestUnzipFundReport(t *testing.T) {
+	instancePath := "XBRL/PublicDoc/jpsps070000-asr-001_G00001-000_2024-01-15_01_2024-04-10.xbrl"
+	zipBody := createTestZip(t, map[string]string{
+		instancePath: fundTestInstance(),
+		"XBRL/AuditDoc/jpaud-aai-cc-001_G00001-000_2024-01-15_01_2024-04-10.xbrl": `<?xml version="1.0" encoding="UTF-8"?>
+<xbrli:xbrl xmlns:xbrli="http://www.xbrl.org/2003/instance" xmlns:jpaud_cor="http://disclosure.edinet-fsa.go.jp/taxonomy/jpaud/2023-12-01/jpaud_cor"></xbrli:xbrl>`,
+	})
+	archive, err := Unzip(zipBody)
+	if err != nil {
+		t.Fatalf("Unzip error: %v", err)
+	}
+	if archive.Main.Path != instancePath {
+		t.Errorf("Main.Path = %q, want %q", archive.Main.Path, instancePath)
+	}
+
+	summary, err := CreateFundSummaryFromInstance("G00001", "テスト投信株式会社", "2023-01-16", "2024-01-15", archive.Main.Body)
+	if err != nil {
+		t.Fatalf("CreateFundSummaryFromInstance error: %v", err)
+	}
+	want := FundSummary{
+		FundCode:         "G00001",
+		FundName:         "テスト・インデックス・ファンド",
+		PeriodStart:      "2023-01-16",
+		PeriodEnd:        "2024-01-15",
+		NetAssets:        TitleValue{Previous: 10000000000, Current: 12345678900},
+		NAVPerUnit:       FloatTitleValue{Previous: 1.0234, Current: 1.2345},
+		NAVUnitCount:     1,
+		TotalUnits:       TitleValue{Previous: 9771234567, Current: 10000000000},
+		TrustFees:        TitleValue{Previous: -12345, Current: -23456},
+		OperatingExpense: TitleValue{Previous: 15000, Current: 26000},
+		MoneyHeldInTrust: 500000,
+	}
+	if summary != want {
+		t.Errorf("CreateFundSummaryFromInstance =\n%+v\nwant\n%+v", summary, want)
+	}
+	if !ValidateFundSummary(summary) {
+		t.Errorf("ValidateFundSummary = false, want true")
+	}
+}
@@ -0,0 +1,306 @@
+package utils
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 役員の状況の軸
+const DirectorsAxis = "DirectorsAndOtherOfficersAxis"
+
+// 役員の状況の要素 (提出者によって要素名の末尾が異なるため前方一致で探す)
+const (
+	OfficerNamePrefix        = "jpcrp_cor:NameInformationAboutDirectorsAndCorporateAuditors"
+	OfficerTitlePrefix       = "jpcrp_cor:OfficialTitleOrPosition"
+	OfficerDateOfBirthPrefix = "jpcrp_cor:DateOfBirth"
+	OfficerSharesPrefix      = "jpcrp_cor:NumberOfSharesHeld"
+	OfficerElementSuffix     = "InformationAboutDirectorsAndCorporateAuditors"
+)
+
+// 生年月日のパターン (例: 1960年4月1日生)
+var DateOfBirthRe = regexp.MustCompile(`[0-9０-９]+年\s*[0-9０-９]+月`)
+
+/*
+役員の状況とコーポレート・ガバナンスの情報を取得する
+役員は XBRL の事実 (DirectorsAndOtherOfficersAxis) から取得し、見つからない場合は役員の状況の表から取得する
+@returns
+
+	役員・監査法人・監査報酬のいずれも見つからない場合は false
+*/
+func CreateGovernanceSummary(companyName string, periodStart string, periodEnd string, contexts []Context, facts []Fact) (GovernanceSummary, bool) {
+	summary := GovernanceSummary{
+		CompanyName: companyName,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+	}
+
+	officersText := FindTextBlockContaining(facts, "役職名", "生年月日", "所有株式数")
+	summary.Officers = FindOfficers(contexts, facts)
+	if len(summary.Officers) == 0 {
+		summary.Officers = ParseOfficersTable(officersText)
+	}
+
+	// 社外役員・独立役員の判定 (役員の一覧の表は除き、注記の文のみで判定する)
+	outsideSentences := sentencesContaining(NotesText(officersText), "社外取締役", "社外監査役")
+	var independentSentences []string
+	for _, fact := range facts {
+		if strings.HasSuffix(fact.Name, "TextBlock") {
+			independentSentences = append(independentSentences, sentencesContaining(NotesText(html.UnescapeString(fact.Value)), "独立役員")...)
+		}
+	}
+	for i, officer := range summary.Officers {
+		if strings.Contains(officer.Title, "社外") || containsName(outsideSentences, officer.Name) {
+			summary.Officers[i].IsOutside = true
+		}
+		summary.Officers[i].IsIndependent = containsName(independentSentences, officer.Name)
+		if strings.Contains(officer.Title, "取締役") {
+			summary.BoardSize++
+			if summary.Officers[i].IsOutside {
+				summary.OutsideDirectors++
+			}
+		}
+	}
+
+	// 監査法人名は監査の状況から取得する
+	for _, fact := range facts {
+		if !strings.HasSuffix(fact.Name, "TextBlock") {
+			continue
+		}
+		text := TagRe.ReplaceAllString(html.UnescapeString(fact.Value), "\n")
+		_, afterTitle, ok := strings.Cut(text, "監査法人の名称")
+		if !ok {
+			continue
+		}
+		summary.AuditFirm = FindAuditorName(afterTitle)
+		if summary.AuditFirm != "" {
+			break
+		}
+	}
+	summary.AuditFees = ParseAuditFeesTable(FindTextBlockContaining(facts, "監査証明業務に基づく報酬"))
+
+	isEmpty := len(summary.Officers) == 0 && summary.AuditFirm == "" && summary.AuditFees == (TitleValue{})
+	return summary, !isEmpty
+}
+
+/*
+XBRL の事実から役員を取得する
+コンテキストは FilingDateInstant_{提出者別のメンバー} の形式
+*/
+func FindOfficers(contexts []Context, facts []Fact) []Officer {
+	// コンテキスト ID → 役員の位置
+	officerIndexes := make(map[string]int)
+	var officers []Officer
+	for _, context := range contexts {
+		if !strings.HasPrefix(context.ID, "FilingDateInstant_") || len(context.Entity.Segment.ExplicitMembers) != 1 {
+			continue
+		}
+		if !strings.HasSuffix(context.Entity.Segment.ExplicitMembers[0].Dimension, DirectorsAxis) {
+			continue
+		}
+		officerIndexes[context.ID] = len(officers)
+		officers = append(officers, Officer{})
+	}
+
+	for _, fact := range facts {
+		index, ok := officerIndexes[fact.ContextRef]
+		if !ok || fact.IsNil || !strings.HasSuffix(fact.Name, OfficerElementSuffix) {
+			continue
+		}
+		value := strings.Join(strings.Fields(fact.Value), " ")
+		switch {
+		case strings.HasPrefix(fact.Name, OfficerNamePrefix):
+			officers[index].Name = value
+		case strings.HasPrefix(fact.Name, OfficerTitlePrefix):
+			officers[index].Title = value
+		case strings.HasPrefix(fact.Name, OfficerDateOfBirthPrefix):
+			officers[index].DateOfBirth = value
+		case strings.HasPrefix(fact.Name, OfficerSharesPrefix):
+			officers[index].Shares, _ = strconv.Atoi(value)
+		}
+	}
+
+	// 氏名がない (役員以外のメンバー) ものは除く
+	var results []Officer
+	for _, officer := range officers {
+		if officer.Name != "" {
+			results = append(results, officer)
+		}
+	}
+	return results
+}
+
+/*
+役員の状況の表から役員を取得する
+役職名, 氏名, 生年月日, 略歴, 任期, 所有株式数 の列の順とし、生年月日の列がない行は除く
+*/
+func ParseOfficersTable(textBlock string) []Officer {
+	if textBlock == "" {
+		return nil
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(textBlock))
+	if err != nil {
+		fmt.Println("役員の状況の goquery.NewDocumentFromReader error: ", err)
+		return nil
+	}
+
+	// 所有株式数の単位
+	multiplier := 1
+	if strings.Contains(doc.Text(), "千株") {
+		multiplier = 1000
+	} else if strings.Contains(doc.Text(), "百株") {
+		multiplier = 100
+	}
+
+	var officers []Officer
+	doc.Find("tr").Each(func(i int, tr *goquery.Selection) {
+		var cells []string
+		tr.Children().Each(func(j int, td *goquery.Selection) {
+			cells = append(cells, strings.Join(strings.Fields(td.Text()), " "))
+		})
+		if len(cells) < 4 || !DateOfBirthRe.MatchString(cells[2]) {
+			return
+		}
+		officer := Officer{
+			Title:       cells[0],
+			Name:        cells[1],
+			DateOfBirth: cells[2],
+		}
+		shares, err := ConvertTextValue2IntValue(cells[len(cells)-1])
+		if err == nil {
+			officer.Shares = shares * multiplier
+		}
+		officers = append(officers, officer)
+	})
+	return officers
+}
+
+/*
+監査報酬の内容の表から監査証明業務に基づく報酬 (前期・当期) を取得する
+区分, 前期の監査証明業務, 前期の非監査業務, 当期の監査証明業務, 当期の非監査業務 の列の順とし、「計」の行 (ない場合は「提出会社」の行) を使う
+*/
+func ParseAuditFeesTable(textBlock string) TitleValue {
+	if textBlock == "" {
+		return TitleValue{}
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(textBlock))
+	if err != nil {
+		fmt.Println("監査報酬の goquery.NewDocumentFromReader error: ", err)
+		return TitleValue{}
+	}
+
+	multiplier := 1
+	if strings.Contains(doc.Text(), "百万円") {
+		multiplier = 1000000
+	} else if strings.Contains(doc.Text(), "千円") {
+		multiplier = 1000
+	}
+
+	var fees TitleValue
+	found := false
+	doc.Find("table").First().Find("tr").Each(func(i int, tr *goquery.Selection) {
+		var cells []string
+		tr.Children().Each(func(j int, td *goquery.Selection) {
+			cells = append(cells, strings.Join(strings.Fields(td.Text()), ""))
+		})
+		if len(cells) != 5 {
+			return
+		}
+		isTotal := cells[0] == "計"
+		if !isTotal && (found || cells[0] != "提出会社") {
+			return
+		}
+		previous, _ := ConvertTextValue2IntValue(cells[1])
+		current, _ := ConvertTextValue2IntValue(cells[3])
+		fees = TitleValue{
+			Previous: previous * multiplier,
+			Current:  current * multiplier,
+		}
+		found = true
+	})
+	return fees
+}
+
+/*
+すべての文字列を含む TextBlock の HTML を探す
+見つからない場合は空文字を返す
+*/
+func FindTextBlockContaining(facts []Fact, keywords ...string) string {
+	for _, fact := range facts {
+		if !strings.HasSuffix(fact.Name, "TextBlock") {
+			continue
+		}
+		text := html.UnescapeString(fact.Value)
+		containsAll := true
+		for _, keyword := range keywords {
+			if !strings.Contains(text, keyword) {
+				containsAll = false
+				break
+			}
+		}
+		if containsAll {
+			return text
+		}
+	}
+	return ""
+}
+
+/*
+TextBlock の HTML から役員の一覧の表 (生年月日の列がある表) を除いた文 (注記など) のテキストを取得する
+タグは改行に置き換え、表のセルや段落が 1 つの文につながらないようにする
+*/
+func NotesText(textBlock string) string {
+	if textBlock == "" {
+		return ""
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(textBlock))
+	if err != nil {
+		fmt.Println("注記の goquery.NewDocumentFromReader error: ", err)
+		return ""
+	}
+	doc.Find("table").FilterFunction(func(i int, table *goquery.Selection) bool {
+		return DateOfBirthRe.MatchString(table.Text())
+	}).Remove()
+	body, err := doc.Find("body").Html()
+	if err != nil {
+		return ""
+	}
+	return html.UnescapeString(TagRe.ReplaceAllString(body, "\n"))
+}
+
+// いずれかの文字列を含む文 (「。」もしくは改行区切り) を返す
+func sentencesContaining(text string, keywords ...string) []string {
+	var sentences []string
+	for _, sentence := range strings.FieldsFunc(text, func(r rune) bool { return r == '。' || r == '\n' }) {
+		for _, keyword := range keywords {
+			if strings.Contains(sentence, keyword) {
+				sentences = append(sentences, sentence)
+				break
+			}
+		}
+	}
+	return sentences
+}
+
+// 空白を除いた氏名が文に含まれるかどうか
+func containsName(sentences []string, name string) bool {
+	name = removeSpaces(name)
+	if name == "" {
+		return false
+	}
+	for _, sentence := range sentences {
+		if strings.Contains(removeSpaces(sentence), name) {
+			return true
+		}
+	}
+	return false
+}
+
+func removeSpaces(text string) string {
+	// 全角スペースも strings.Fields で区切られる
+	return strings.Join(strings.Fields(text), "")
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"testing"
+)
+
+// 役員の状況の TextBlock の役員の一覧の表
+const officersTable = `<h3>役員の状況</h3>
+<table>
+<tr><td><p>役職名</p></td><td><p>氏名</p></td><td><p>生年月日</p></td><td><p>略歴</p></td><td><p>任期</p></td><td><p>所有株式数(千株)</p></td></tr>
+<tr><td><p>代表取締役社長</p></td><td><p>山田　太郎</p></td><td><p>1960年4月1日生</p></td><td><p>1983年4月 当社入社</p></td><td><p>(注)3</p></td><td><p>120</p></td></tr>
+<tr><td><p>取締役</p></td><td><p>鈴木　一郎</p></td><td><p>1955年5月2日生</p></td><td><p>2015年6月 〇〇株式会社 社外取締役</p></td><td><p>(注)3</p></td><td><p>―</p></td></tr>
+</table>
+`
+
+// 役員の状況の TextBlock の注記
+const officersNotes = `<p>(注)1.&#160;取締役鈴木一郎は、社外取締役であります。</p>
+<p>2.&#160;当社は、鈴木一郎を東京証券取引所の定めに基づく独立役員として指定し、同取引所に届け出ております。</p>`
+
+func TestCreateGovernanceSummaryOutsideDirectors(t *testing.T) {
+	tests := []struct {
+		name              string
+		textBlock         string
+		wantOutside       map[string]bool
+		wantIndependent   map[string]bool
+		wantBoardSize     int
+		wantOutsideBoards int
+	}{
+		{
+			name:              "注記で社外取締役・独立役員を判定する",
+			textBlock:         officersTable + officersNotes,
+			wantOutside:       map[string]bool{"山田 太郎": false, "鈴木 一郎": true},
+			wantIndependent:   map[string]bool{"山田 太郎": false, "鈴木 一郎": true},
+			wantBoardSize:     2,
+			wantOutsideBoards: 1,
+		},
+		{
+			name:              "注記がない場合は社外取締役なし (略歴の「社外取締役」は対象外)",
+			textBlock:         officersTable,
+			wantOutside:       map[string]bool{"山田 太郎": false, "鈴木 一郎": false},
+			wantIndependent:   map[string]bool{"山田 太郎": false, "鈴木 一郎": false},
+			wantBoardSize:     2,
+			wantOutsideBoards: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			facts := []Fact{{Name: "jpcrp_cor:InformationAboutOfficersTextBlock", ContextRef: "FilingDateInstant", Value: tt.textBlock}}
+			summary, ok := CreateGovernanceSummary("テスト株式会社", "2023-04-01", "2024-03-31", nil, facts)
+			if !ok {
+				t.Fatal("CreateGovernanceSummary = false, want true")
+			}
+			if len(summary.Officers) != len(tt.wantOutside) {
+				t.Fatalf("Officers = %+v, want %d 名", summary.Officers, len(tt.wantOutside))
+			}
+			for _, officer := range summary.Officers {
+				if officer.IsOutside != tt.wantOutside[officer.Name] {
+					t.Errorf("%s (%s) IsOutside = %v, want %v", officer.Name, officer.Title, officer.IsOutside, tt.wantOutside[officer.Name])
+				}
+				if officer.IsIndependent != tt.wantIndependent[officer.Name] {
+					t.Errorf("%s (%s) IsIndependent = %v, want %v", officer.Name, officer.Title, officer.IsIndependent, tt.wantIndependent[officer.Name])
+				}
+			}
+			if summary.BoardSize != tt.wantBoardSize || summary.OutsideDirectors != tt.wantOutsideBoards {
+				t.Errorf("BoardSize = %d, OutsideDirectors = %d, want %d, %d", summary.BoardSize, summary.OutsideDirectors, tt.wantBoardSize, tt.wantOutsideBoards)
+			}
+		})
+	}
+}
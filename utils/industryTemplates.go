@@ -0,0 +1,295 @@
+package utils
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 業種別テンプレートの種類
+const (
+	IndustryGeneral    = "general"    // 一般商工業など
+	IndustryBank       = "bank"       // 銀行・信託業
+	IndustryInsurance  = "insurance"  // 生命保険業・損害保険業
+	IndustrySecurities = "securities" // 第一種金融商品取引業
+)
+
+// 業種別テンプレートの勘定科目
+type IndustryField struct {
+	Key      string   // industry_items のキー
+	Titles   []string // 完全一致で探す勘定科目
+	Prefixes []string // 前方一致で探す勘定科目
+}
+
+// 業種別のサマリーのテンプレート
+type IndustryTemplate struct {
+	Industry          string
+	Label             string
+	PLFields          []IndustryField
+	RequiredPLFields  []string // バリデーションで必須とする PL の勘定科目 (前期・当期のいずれかが 0 でないこと)
+	RevenueField      string   // ファンダメンタルの収益に使う勘定科目
+	ProfitField       string   // ファンダメンタルの利益に使う勘定科目
+	LiabilitiesTitles []string // 負債合計の代わりに使う勘定科目
+	NetAssetsTitles   []string // 純資産合計の代わりに使う勘定科目
+}
+
+// 経常利益の勘定科目 (銀行・保険・証券で共通)
+var ordinaryProfitField = IndustryField{
+	Key:    "ordinary_profit",
+	Titles: []string{"経常利益", "経常損失（△）", "経常利益又は経常損失（△）"},
+}
+
+var IndustryTemplates = map[string]IndustryTemplate{
+	IndustryGeneral: {
+		Industry: IndustryGeneral,
+		Label:    "一般",
+	},
+	IndustryBank: {
+		Industry: IndustryBank,
+		Label:    "銀行・信託業",
+		PLFields: []IndustryField{
+			{Key: "ordinary_revenue", Titles: []string{"経常収益", "経常収益合計"}},
+			{Key: "interest_income", Titles: []string{"資金運用収益"}},
+			{Key: "fees_and_commissions", Titles: []string{"役務取引等収益"}},
+			{Key: "ordinary_expenses", Titles: []string{"経常費用", "経常費用合計"}},
+			{Key: "interest_expenses", Titles: []string{"資金調達費用"}},
+			ordinaryProfitField,
+		},
+		RequiredPLFields:  []string{"ordinary_revenue", "ordinary_profit"},
+		RevenueField:      "ordinary_revenue",
+		ProfitField:       "ordinary_profit",
+		LiabilitiesTitles: []string{"負債の部合計"},
+		NetAssetsTitles:   []string{"純資産の部合計"},
+	},
+	IndustryInsurance: {
+		Industry: IndustryInsurance,
+		Label:    "保険業",
+		PLFields: []IndustryField{
+			{Key: "ordinary_revenue", Titles: []string{"経常収益", "経常収益合計"}},
+			{Key: "insurance_premiums", Titles: []string{"保険料等収入", "保険引受収益"}},
+			{Key: "investment_income", Titles: []string{"資産運用収益"}},
+			{Key: "ordinary_expenses", Titles: []string{"経常費用", "経常費用合計"}},
+			{Key: "insurance_claims", Titles: []string{"保険金等支払金", "保険引受費用"}},
+			ordinaryProfitField,
+		},
+		RequiredPLFields:  []string{"ordinary_revenue", "ordinary_profit"},
+		RevenueField:      "ordinary_revenue",
+		ProfitField:       "ordinary_profit",
+		LiabilitiesTitles: []string{"負債の部合計"},
+		NetAssetsTitles:   []string{"純資産の部合計"},
+	},
+	IndustrySecurities: {
+		Industry: IndustrySecurities,
+		Label:    "第一種金融商品取引業",
+		PLFields: []IndustryField{
+			{Key: "operating_revenue", Titles: []string{"営業収益", "営業収益合計"}},
+			{Key: "commissions", Titles: []string{"受入手数料"}},
+			{Key: "trading_gains", Titles: []string{"トレーディング損益"}},
+			{Key: "financial_revenue", Titles: []string{"金融収益"}},
+			{Key: "net_operating_revenue", Titles: []string{"純営業収益"}},
+			{Key: "sg_and_a", Titles: []string{"販売費・一般管理費", "販売費及び一般管理費"}},
+			{Key: "operating_profit", Titles: []string{"営業利益", "営業損失（△）", "営業利益又は営業損失（△）"}},
+			ordinaryProfitField,
+		},
+		RequiredPLFields:  []string{"operating_revenue", "net_operating_revenue"},
+		RevenueField:      "operating_revenue",
+		ProfitField:       "operating_profit",
+		LiabilitiesTitles: []string{"負債の部合計"},
+		NetAssetsTitles:   []string{"純資産の部合計"},
+	},
+}
+
+/*
+業種別テンプレートを選ぶ
+DEI の業種コード (bk1, bk2, in1, in2, sec など) を優先し、ない場合は業種別の要素 (接尾辞 BNK, INS, SEC) があるかどうかで判定する
+*/
+func SelectIndustryTemplate(industryCode string, facts []Fact) IndustryTemplate {
+	switch {
+	case strings.HasPrefix(industryCode, "bk"):
+		return IndustryTemplates[IndustryBank]
+	case industryCode == "in1" || industryCode == "in2":
+		return IndustryTemplates[IndustryInsurance]
+	case industryCode == "sec":
+		return IndustryTemplates[IndustrySecurities]
+	case industryCode != "":
+		return IndustryTemplates[IndustryGeneral]
+	}
+
+	for _, fact := range facts {
+		if !strings.HasPrefix(fact.Name, "jppfs_cor:") {
+			continue
+		}
+		switch {
+		case strings.HasSuffix(fact.Name, "BNK"):
+			return IndustryTemplates[IndustryBank]
+		case strings.HasSuffix(fact.Name, "INS"):
+			return IndustryTemplates[IndustryInsurance]
+		case strings.HasSuffix(fact.Name, "SEC"):
+			return IndustryTemplates[IndustrySecurities]
+		}
+	}
+	return IndustryTemplates[IndustryGeneral]
+}
+
+/*
+業種別テンプレートの勘定科目を PL のサマリーとファンダメンタルに設定する
+一般のテンプレートの場合は業種のみ設定する
+*/
+func UpdateIndustryPLSummary(doc *goquery.Document, template IndustryTemplate, plSummary *PLSummary, fundamental *Fundamental) {
+	plSummary.Industry = template.Industry
+	if fundamental != nil {
+		fundamental.Industry = template.Industry
+	}
+	if len(template.PLFields) == 0 {
+		return
+	}
+
+	if plSummary.IndustryItems == nil {
+		plSummary.IndustryItems = make(map[string]TitleValue)
+	}
+	for _, row := range statementRows(doc) {
+		for _, field := range template.PLFields {
+			if _, ok := plSummary.IndustryItems[field.Key]; ok {
+				// 最初に見つかった行を使う
+				continue
+			}
+			if !matchTitle(row.titleName, field) {
+				continue
+			}
+			plSummary.IndustryItems[field.Key] = row.titleValue
+		}
+	}
+
+	if fundamental != nil {
+		fundamental.IndustryRevenue = plSummary.IndustryItems[template.RevenueField].Current
+		fundamental.IndustryProfit = plSummary.IndustryItems[template.ProfitField].Current
+	}
+}
+
+/*
+業種別テンプレートの負債・純資産の勘定科目を BS のサマリーとファンダメンタルに設定する
+*/
+func UpdateIndustryBSSummary(doc *goquery.Document, template IndustryTemplate, summary *Summary, fundamental *Fundamental) {
+	if len(template.LiabilitiesTitles) == 0 && len(template.NetAssetsTitles) == 0 {
+		return
+	}
+	for _, row := range statementRows(doc) {
+		if slices.Contains(template.LiabilitiesTitles, row.titleName) && summary.Liabilities == (TitleValue{}) {
+			summary.Liabilities = row.titleValue
+			if fundamental != nil {
+				fundamental.Liabilities = row.titleValue.Current
+			}
+		}
+		if slices.Contains(template.NetAssetsTitles, row.titleName) && summary.NetAssets == (TitleValue{}) {
+			summary.NetAssets = row.titleValue
+			if fundamental != nil {
+				fundamental.NetAssets = row.titleValue.Current
+			}
+		}
+	}
+}
+
+/*
+業種別テンプレートに従って PL のサマリーを検証する
+一般のテンプレートの場合は ValidatePLSummary を使う
+*/
+func ValidateIndustryPLSummary(plSummary PLSummary, template IndustryTemplate) bool {
+	if len(template.RequiredPLFields) == 0 {
+		return ValidatePLSummary(plSummary)
+	}
+	if plSummary.CompanyName == "" || plSummary.PeriodStart == "" || plSummary.PeriodEnd == "" {
+		return false
+	}
+	for _, key := range template.RequiredPLFields {
+		if plSummary.IndustryItems[key] == (TitleValue{}) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+業種別テンプレートに従ってファンダメンタルを検証する
+一般のテンプレートの場合は ValidateFundamentals を使う
+*/
+func ValidateIndustryFundamentals(fundamental Fundamental, template IndustryTemplate) bool {
+	if template.RevenueField == "" {
+		return ValidateFundamentals(fundamental)
+	}
+	return fundamental.CompanyName != "" &&
+		fundamental.PeriodStart != "" &&
+		fundamental.PeriodEnd != "" &&
+		fundamental.IndustryRevenue != 0 &&
+		fundamental.IndustryProfit != 0 &&
+		fundamental.Liabilities != 0 &&
+		fundamental.NetAssets != 0
+}
+
+func matchTitle(titleName string, field IndustryField) bool {
+	normalizedTitle := NormalizeLabel(titleName)
+	if slices.ContainsFunc(field.Titles, func(title string) bool { return NormalizeLabel(title) == normalizedTitle }) {
+		return true
+	}
+	for _, prefix := range field.Prefixes {
+		if strings.HasPrefix(titleName, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// 財務諸表の行 (勘定科目と前期・当期の値)
+type statementRow struct {
+	titleName  string
+	titleValue TitleValue
+}
+
+/*
+財務諸表の HTML から値のある行を取得する
+見出し行から前期・当期の列が分かる場合は列の位置、分からない場合は UpdateEverySummary と同じく
+列が 4 つ以上ある場合は 3, 4 列目、3 つの場合は 2, 3 列目を前期・当期の値とする
+*/
+func statementRows(doc *goquery.Document) []statementRow {
+	columns, hasColumns := DetectStatementColumns(doc)
+	var rows []statementRow
+	doc.Find("tr").Each(func(i int, s *goquery.Selection) {
+		var titleTexts []string
+		for _, t := range strings.Split(strings.TrimSpace(s.Find("td").Text()), "\n") {
+			if t != "" {
+				titleTexts = append(titleTexts, t)
+			}
+		}
+		if len(titleTexts) == 0 {
+			return
+		}
+		var previousText, currentText string
+		switch {
+		case hasColumns:
+			var ok bool
+			previousText, currentText, ok = columns.CellTexts(s)
+			if !ok {
+				return
+			}
+		case len(titleTexts) >= 4:
+			previousText, currentText = titleTexts[2], titleTexts[3]
+		case len(titleTexts) == 3:
+			previousText, currentText = titleTexts[1], titleTexts[2]
+		default:
+			return
+		}
+		previous, err := ConvertTextValue2IntValue(previousText)
+		if err != nil {
+			return
+		}
+		current, err := ConvertTextValue2IntValue(currentText)
+		if err != nil {
+			return
+		}
+		rows = append(rows, statementRow{
+			titleName:  titleTexts[0],
+			titleValue: TitleValue{Previous: previous, Current: current},
+		})
+	})
+	return rows
+}
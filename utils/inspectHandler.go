@@ -0,0 +1,292 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// inspect-xbrl の結果
+type Inspection struct {
+	FileName   string             `json:"file_name"`
+	Source     string             `json:"source"` // TextBlock の取得元 (xbrl もしくは ixbrl)
+	TextBlocks []InspectTextBlock `json:"text_blocks"`
+	Statements []InspectStatement `json:"statements"`
+}
+
+// TextBlock の正規表現ごとのマッチ結果
+type InspectTextBlock struct {
+	StatementType string `json:"statement_type"` // BS, PL, CF
+	Label         string `json:"label"`          // 財務諸表の名称
+	Matched       bool   `json:"matched"`        // 正規表現にマッチしたかどうか
+	Selected      bool   `json:"selected"`       // パース対象に選ばれたかどうか
+}
+
+// 財務諸表ごとの抽出過程
+type InspectStatement struct {
+	StatementType string       `json:"statement_type"`
+	Label         string       `json:"label"`
+	Rows          []InspectRow `json:"rows"`
+	Summary       interface{}  `json:"summary"`
+}
+
+// <tr> ごとの抽出過程
+type InspectRow struct {
+	Index       int                 `json:"index"`
+	TitleTexts  []string            `json:"title_texts"`
+	Assignments []InspectAssignment `json:"assignments,omitempty"`
+	SkipReason  string              `json:"skip_reason,omitempty"`
+}
+
+// 行から設定されたサマリーの項目
+type InspectAssignment struct {
+	Field    string `json:"field"`
+	Previous int    `json:"previous"`
+	Current  int    `json:"current"`
+	Text     string `json:"text,omitempty"`
+}
+
+/*
+inspect 中であれば行を記録する
+inspect 中でなければ nil を返す
+*/
+func (s *InspectStatement) row(index int, titleTexts []string) *InspectRow {
+	if s == nil {
+		return nil
+	}
+	s.Rows = append(s.Rows, InspectRow{
+		Index:      index,
+		TitleTexts: titleTexts,
+	})
+	return &s.Rows[len(s.Rows)-1]
+}
+
+func (r *InspectRow) assign(field string, titleValue TitleValue) {
+	if r == nil {
+		return
+	}
+	r.Assignments = append(r.Assignments, InspectAssignment{
+		Field:    field,
+		Previous: titleValue.Previous,
+		Current:  titleValue.Current,
+	})
+}
+
+func (r *InspectRow) assignText(field string, text string) {
+	if r == nil {
+		return
+	}
+	r.Assignments = append(r.Assignments, InspectAssignment{
+		Field: field,
+		Text:  text,
+	})
+}
+
+func (r *InspectRow) skip(reason string) {
+	if r == nil {
+		return
+	}
+	r.SkipReason = reason
+}
+
+/*
+ローカルの XBRL ファイルまたは zip ファイルを読み込み、抽出過程を返す
+*/
+func InspectFile(path string) (*Inspection, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := filepath.Base(path)
+	var manuscripts []IXBRLDocument
+	var labelFiles []ArchiveFile
+	if filepath.Ext(path) == ".zip" {
+		archive, err := Unzip(body)
+		if err != nil {
+			return nil, err
+		}
+		fileName = filepath.Base(archive.Main.Path)
+		body = archive.Main.Body
+		manuscripts = archive.Manuscripts
+		labelFiles = archive.LabelFiles
+	}
+	return InspectXBRL(fileName, body, manuscripts, labelFiles), nil
+}
+
+/*
+XBRL ファイルから BS, PL, CF の抽出過程を記録する
+manuscripts: ZIP 内のインライン XBRL (ない場合は nil)
+labelFiles:  ZIP 内の名称リンクベース (ない場合は nil)
+*/
+func InspectXBRL(fileName string, body []byte, manuscripts []IXBRLDocument, labelFiles []ArchiveFile) *Inspection {
+	labelDictionary := FilingLabelDictionary(labelFiles)
+	inspection := &Inspection{FileName: fileName, Source: "xbrl"}
+	matches := ResolveStatementMatches(body, manuscripts)
+	if matches.FromIXBRL {
+		inspection.Source = "ixbrl"
+	}
+
+	candidates := []struct {
+		statementType string
+		label         string
+		match         string
+	}{
+		{"BS", "連結財政状態計算書", matches.ConsolidatedBSIFRS},
+		{"BS", "連結貸借対照表", matches.ConsolidatedBS},
+		{"BS", "貸借対照表", matches.SoloBS},
+		{"PL", "連結損益計算書（IFRS）", matches.ConsolidatedPLIFRS},
+		{"PL", "連結純損益及びその他の包括利益計算書（IFRS）", matches.ConsolidatedPLCIIFRS},
+		{"PL", "連結損益計算書", matches.ConsolidatedPL},
+		{"PL", "連結損益及び包括利益計算書", matches.ConsolidatedPLCI},
+		{"PL", "損益計算書", matches.SoloPL},
+		{"CI", "連結包括利益計算書（IFRS）", matches.ConsolidatedCIIFRS},
+		{"CI", "連結包括利益計算書", matches.ConsolidatedCI},
+		{"CF", "連結キャッシュ・フロー計算書 (IFRS)", matches.ConsolidatedCFIFRS},
+		{"CF", "連結キャッシュ・フロー計算書", matches.ConsolidatedCF},
+		{"CF", "キャッシュ・フロー計算書 (IFRS)", matches.SoloCFIFRS},
+		{"CF", "キャッシュ・フロー計算書", matches.SoloCF},
+		{"SS", "連結持分変動計算書", matches.ConsolidatedSSIFRS},
+		{"SS", "連結株主資本等変動計算書", matches.ConsolidatedSS},
+		{"SS", "株主資本等変動計算書", matches.SoloSS},
+	}
+
+	for _, statementType := range []string{"BS", "PL", "CI", "CF", "SS"} {
+		selectedLabel, match := SelectStatementMatch(statementType, matches)
+		for _, c := range candidates {
+			if c.statementType != statementType {
+				continue
+			}
+			inspection.TextBlocks = append(inspection.TextBlocks, InspectTextBlock{
+				StatementType: c.statementType,
+				Label:         c.label,
+				Matched:       c.match != "",
+				Selected:      c.label == selectedLabel,
+			})
+		}
+
+		statement := InspectStatement{
+			StatementType: statementType,
+			Label:         selectedLabel,
+		}
+		if match != "" {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(matches.StatementHTML(match)))
+			if err != nil {
+				fmt.Println("inspect goquery.NewDocumentFromReader error: ", err)
+			} else {
+				statement.Summary = inspectSummary(doc, statementType, &statement, labelDictionary)
+			}
+		}
+		inspection.Statements = append(inspection.Statements, statement)
+	}
+	return inspection
+}
+
+/*
+UpdateEverySummary を実行し、行ごとの判定を記録する
+*/
+func inspectSummary(doc *goquery.Document, statementType string, statement *InspectStatement, labelDictionary *LabelDictionary) interface{} {
+	var fundamental Fundamental
+	var result interface{}
+	switch statementType {
+	case "BS":
+		var summary Summary
+		UpdateEverySummary(doc, "", "", "bs", &summary, nil, nil, &fundamental, labelDictionary, statement)
+		result = summary
+	case "PL", "CI":
+		// 包括利益計算書は損益計算書のサマリーに包括利益を設定する
+		var plSummary PLSummary
+		UpdateEverySummary(doc, "", "", "pl", nil, &plSummary, nil, &fundamental, labelDictionary, statement)
+		result = plSummary
+	case "CF":
+		var cfSummary CFSummary
+		UpdateEverySummary(doc, "", "", "cf", nil, nil, &cfSummary, nil, labelDictionary, statement)
+		CompleteCFSummary(&cfSummary)
+		result = cfSummary
+	case "SS":
+		// 株主資本等変動計算書は行ごとの判定を記録しない
+		var ssSummary SSSummary
+		UpdateSSSummary(doc, &ssSummary)
+		result = ssSummary
+	}
+
+	// 項目が設定されなかった行にスキップ理由を設定
+	for i := range statement.Rows {
+		row := &statement.Rows[i]
+		if len(row.Assignments) > 0 || row.SkipReason != "" {
+			continue
+		}
+		switch {
+		case len(row.TitleTexts) == 0:
+			row.SkipReason = "テキストなし"
+		case len(row.TitleTexts) < 3:
+			row.SkipReason = "金額列なし"
+		default:
+			row.SkipReason = "一致する項目なし"
+		}
+	}
+	return result
+}
+
+/*
+抽出過程を出力する
+@params
+
+	format: text もしくは json
+*/
+func PrintInspection(w io.Writer, inspection *Inspection, format string) error {
+	if format == "json" {
+		jsonBody, err := json.MarshalIndent(inspection, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(jsonBody))
+		return err
+	}
+
+	fmt.Fprintf(w, "===== %s (%s) =====\n", inspection.FileName, inspection.Source)
+	fmt.Fprintln(w, "【TextBlock】")
+	for _, textBlock := range inspection.TextBlocks {
+		matchedStr := "なし"
+		if textBlock.Matched {
+			matchedStr = "あり"
+		}
+		selectedStr := ""
+		if textBlock.Selected {
+			selectedStr = " ← 使用"
+		}
+		fmt.Fprintf(w, "  [%s] %s: %s%s\n", textBlock.StatementType, textBlock.Label, matchedStr, selectedStr)
+	}
+
+	for _, statement := range inspection.Statements {
+		fmt.Fprintf(w, "\n【%s】%s\n", statement.StatementType, statement.Label)
+		if statement.Label == "" {
+			fmt.Fprintln(w, "  パース対象の TextBlock がありません")
+			continue
+		}
+		for _, row := range statement.Rows {
+			fmt.Fprintf(w, "  #%d [%s]", row.Index, strings.Join(row.TitleTexts, " | "))
+			if row.SkipReason != "" {
+				fmt.Fprintf(w, " × %s\n", row.SkipReason)
+				continue
+			}
+			var assignedStrs []string
+			for _, a := range row.Assignments {
+				if a.Text != "" {
+					assignedStrs = append(assignedStrs, fmt.Sprintf("%s (%s)", a.Field, a.Text))
+				} else {
+					assignedStrs = append(assignedStrs, fmt.Sprintf("%s (前期: %d, 当期: %d)", a.Field, a.Previous, a.Current))
+				}
+			}
+			fmt.Fprintf(w, " → %s\n", strings.Join(assignedStrs, ", "))
+		}
+		jsonBody, _ := json.MarshalIndent(statement.Summary, "  ", "  ")
+		fmt.Fprintf(w, "  サマリー:\n  %s\n", string(jsonBody))
+	}
+	return nil
+}
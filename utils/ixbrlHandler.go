@@ -0,0 +1,378 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// インライン XBRL の名前空間
+const IXNamespace = "http://www.xbrl.org/2013/inlineXBRL"
+
+// ix:nonFraction, ix:nonNumeric, ix:continuation の属性
+type ixAttrs struct {
+	Name        string
+	ContextRef  string
+	UnitRef     string
+	Decimals    string
+	Scale       string
+	Sign        string
+	Format      string
+	IsNil       bool
+	ID          string // ix:continuation の ID
+	ContinuedAt string // 続きの ix:continuation の ID
+}
+
+// 読み込み中の ix 要素
+type ixElement struct {
+	local       string
+	attrs       ixAttrs
+	text        strings.Builder
+	innerOffset int64 // 開始タグの直後の位置
+}
+
+/*
+インライン XBRL (*_ixbrl.htm) から事実を取得する
+ix:nonFraction は scale, sign, format を適用し、XBRL インスタンスと同じ形式の値にする
+TextBlock は ix:continuation (continuedAt) で分割されている場合は結合する
+変換できない数値はログに出力してスキップする
+*/
+func ParseIXBRLFacts(body []byte) ([]Fact, error) {
+	var facts []Fact
+	var continuedAts []string
+	continuations := make(map[string]ixContinuation)
+	err := walkIXBRL(body, func(element *ixElement, innerHTML string) error {
+		if element.local == "continuation" {
+			continuations[element.attrs.ID] = ixContinuation{html: innerHTML, continuedAt: element.attrs.ContinuedAt}
+			return nil
+		}
+		fact := Fact{
+			Name:       element.attrs.Name,
+			ContextRef: element.attrs.ContextRef,
+			UnitRef:    element.attrs.UnitRef,
+			Decimals:   element.attrs.Decimals,
+			IsNil:      element.attrs.IsNil,
+		}
+		if element.local == "nonFraction" && !element.attrs.IsNil {
+			value, err := NormalizeIXBRLNumber(element.text.String(), element.attrs.Format, element.attrs.Scale, element.attrs.Sign)
+			if err != nil {
+				fmt.Printf("%s (%s) の数値変換エラーのためスキップします: %v\n", element.attrs.Name, element.attrs.ContextRef, err)
+				return nil
+			}
+			fact.Value = value
+		} else if strings.HasSuffix(element.attrs.Name, "TextBlock") {
+			fact.Value = innerHTML
+		} else {
+			fact.Value = strings.TrimSpace(element.text.String())
+		}
+		facts = append(facts, fact)
+		continuedAts = append(continuedAts, element.attrs.ContinuedAt)
+		return nil
+	})
+	for i := range facts {
+		if continuedAts[i] != "" && strings.HasSuffix(facts[i].Name, "TextBlock") {
+			facts[i].Value += joinIXContinuations(continuations, continuedAts[i])
+		}
+	}
+	// 読み込めた部分の事実も返す
+	return facts, err
+}
+
+// ix:continuation の内容と続きの ID
+type ixContinuation struct {
+	html        string
+	continuedAt string
+}
+
+/*
+インライン XBRL から TextBlock (contextRef="CurrentYearDuration") の HTML を取得する
+各マニュスクリプトは 1 回だけ読み込み、ix:continuation で分割されている場合は結合する
+同じ名前の TextBlock が複数ある場合は最初のものを使う
+@returns
+
+	要素名 (例: jpcrp_cor:ConsolidatedBalanceSheetTextBlock) → HTML
+*/
+func CollectIXBRLTextBlocks(documents []IXBRLDocument) map[string]string {
+	textBlocks := make(map[string]string)
+	continuedAts := make(map[string]string)
+	// continuation は別のマニュスクリプトにある場合もあるため、すべて読み込んでから結合する
+	continuations := make(map[string]ixContinuation)
+	for _, document := range documents {
+		err := walkIXBRL(document.Body, func(element *ixElement, innerHTML string) error {
+			switch {
+			case element.local == "continuation":
+				continuations[element.attrs.ID] = ixContinuation{html: innerHTML, continuedAt: element.attrs.ContinuedAt}
+			case element.local == "nonNumeric" && strings.HasSuffix(element.attrs.Name, "TextBlock") && element.attrs.ContextRef == "CurrentYearDuration":
+				if _, exists := textBlocks[element.attrs.Name]; !exists {
+					textBlocks[element.attrs.Name] = innerHTML
+					continuedAts[element.attrs.Name] = element.attrs.ContinuedAt
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Printf("%s の読み込みエラー (読み込めた部分のみ使います): %v\n", document.Path, err)
+		}
+	}
+	for name, continuedAt := range continuedAts {
+		if continuedAt != "" {
+			textBlocks[name] += joinIXContinuations(continuations, continuedAt)
+		}
+	}
+	return textBlocks
+}
+
+// continuedAt から続く ix:continuation の HTML を順に結合する
+func joinIXContinuations(continuations map[string]ixContinuation, continuedAt string) string {
+	var builder strings.Builder
+	visited := make(map[string]bool)
+	for continuedAt != "" && !visited[continuedAt] {
+		visited[continuedAt] = true
+		continuation, ok := continuations[continuedAt]
+		if !ok {
+			fmt.Printf("ix:continuation (id=%s) が見つかりません❗️\n", continuedAt)
+			break
+		}
+		builder.WriteString(continuation.html)
+		continuedAt = continuation.continuedAt
+	}
+	return builder.String()
+}
+
+/*
+インライン XBRL から各財務諸表の TextBlock を取得する
+取得した HTML はエスケープされていないため FromIXBRL を true にする
+*/
+func FindIXBRLStatementMatches(documents []IXBRLDocument) StatementMatches {
+	textBlocks := CollectIXBRLTextBlocks(documents)
+	return StatementMatches{
+		ConsolidatedBS:       textBlocks["jpcrp_cor:ConsolidatedBalanceSheetTextBlock"],
+		ConsolidatedBSIFRS:   textBlocks["jpigp_cor:ConsolidatedStatementOfFinancialPositionIFRSTextBlock"],
+		SoloBS:               textBlocks["jpcrp_cor:BalanceSheetTextBlock"],
+		ConsolidatedPL:       textBlocks["jpcrp_cor:ConsolidatedStatementOfIncomeTextBlock"],
+		ConsolidatedPLIFRS:   textBlocks["jpigp_cor:ConsolidatedStatementOfProfitOrLossIFRSTextBlock"],
+		SoloPL:               textBlocks["jpcrp_cor:StatementOfIncomeTextBlock"],
+		ConsolidatedPLCI:     textBlocks["jpcrp_cor:ConsolidatedStatementOfComprehensiveIncomeSingleStatementTextBlock"],
+		ConsolidatedPLCIIFRS: textBlocks["jpigp_cor:ConsolidatedStatementOfComprehensiveIncomeSingleStatementIFRSTextBlock"],
+		ConsolidatedCI:       textBlocks["jpcrp_cor:ConsolidatedStatementOfComprehensiveIncomeTextBlock"],
+		ConsolidatedCIIFRS:   textBlocks["jpigp_cor:ConsolidatedStatementOfComprehensiveIncomeIFRSTextBlock"],
+		ConsolidatedCF:       textBlocks["jpcrp_cor:ConsolidatedStatementOfCashFlowsTextBlock"],
+		ConsolidatedCFIFRS:   textBlocks["jpigp_cor:ConsolidatedStatementOfCashFlowsIFRSTextBlock"],
+		SoloCF:               textBlocks["jpcrp_cor:StatementOfCashFlowsTextBlock"],
+		SoloCFIFRS:           textBlocks["jpcrp_cor:StatementOfCashFlowsIFRSTextBlock"],
+		ConsolidatedSS:       textBlocks["jpcrp_cor:ConsolidatedStatementOfChangesInEquityTextBlock"],
+		ConsolidatedSSIFRS:   textBlocks["jpigp_cor:ConsolidatedStatementOfChangesInEquityIFRSTextBlock"],
+		SoloSS:               textBlocks["jpcrp_cor:StatementOfChangesInEquityTextBlock"],
+		FromIXBRL:            true,
+	}
+}
+
+/*
+パースに使う TextBlock を決める
+インライン XBRL に BS, PL, CF がそろっている場合はインライン XBRL を優先し、それ以外は XBRL インスタンスの TextBlock を使う
+*/
+func ResolveStatementMatches(body []byte, documents []IXBRLDocument) StatementMatches {
+	if len(documents) > 0 {
+		ixbrlMatches := FindIXBRLStatementMatches(documents)
+		_, bsMatch := SelectStatementMatch("BS", ixbrlMatches)
+		_, plMatch := SelectStatementMatch("PL", ixbrlMatches)
+		_, cfMatch := SelectStatementMatch("CF", ixbrlMatches)
+		if bsMatch != "" && plMatch != "" && cfMatch != "" {
+			return ixbrlMatches
+		}
+	}
+	return FindStatementMatches(string(body))
+}
+
+/*
+ix:nonFraction, ix:nonNumeric, ix:continuation を順に読み込み、終了タグごとに callback を呼ぶ
+ix:nonNumeric の中に ix:nonFraction がある場合も両方の callback が呼ばれる
+*/
+func walkIXBRL(body []byte, callback func(element *ixElement, innerHTML string) error) error {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	// XHTML の &nbsp; などに対応する
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	var stack []*ixElement
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if !isIXElement(t.Name) {
+				continue
+			}
+			element := &ixElement{
+				local:       t.Name.Local,
+				attrs:       readIXAttrs(t.Attr),
+				innerOffset: decoder.InputOffset(),
+			}
+			stack = append(stack, element)
+		case xml.CharData:
+			for _, element := range stack {
+				element.text.Write(t)
+			}
+		case xml.EndElement:
+			if !isIXElement(t.Name) || len(stack) == 0 {
+				continue
+			}
+			element := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			// 終了タグの直前までを内部の HTML とする
+			endOffset := decoder.InputOffset()
+			innerEnd := bytes.LastIndex(body[:endOffset], []byte("</"))
+			var innerHTML string
+			if innerEnd >= int(element.innerOffset) {
+				innerHTML = string(body[element.innerOffset:innerEnd])
+			}
+			err := callback(element, innerHTML)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func isIXElement(name xml.Name) bool {
+	if name.Space != IXNamespace && name.Space != "ix" {
+		return false
+	}
+	return name.Local == "nonFraction" || name.Local == "nonNumeric" || name.Local == "continuation"
+}
+
+func readIXAttrs(attrs []xml.Attr) ixAttrs {
+	var result ixAttrs
+	for _, attr := range attrs {
+		switch attr.Name.Local {
+		case "name":
+			result.Name = attr.Value
+		case "contextRef":
+			result.ContextRef = attr.Value
+		case "unitRef":
+			result.UnitRef = attr.Value
+		case "decimals":
+			result.Decimals = attr.Value
+		case "scale":
+			result.Scale = attr.Value
+		case "sign":
+			result.Sign = attr.Value
+		case "format":
+			result.Format = attr.Value
+		case "nil":
+			result.IsNil = attr.Value == "true"
+		case "id":
+			result.ID = attr.Value
+		case "continuedAt":
+			result.ContinuedAt = attr.Value
+		}
+	}
+	return result
+}
+
+/*
+ix:nonFraction の表示値を XBRL インスタンスと同じ形式の数値文字列に変換する
+@params
+
+	text:   表示されている値 (例: 1,234)
+	format: ixt:numdotdecimal, ixt:numcommadecimal, ixt:zerodash, ixt:fixed-zero など
+	scale:  10 の何乗倍するか (例: 百万円なら 6)
+	sign:   "-" の場合は負の値
+*/
+func NormalizeIXBRLNumber(text string, format string, scale string, sign string) (string, error) {
+	text = strings.TrimSpace(html.UnescapeString(text))
+	// 接頭辞 (ixt:, ixt-sec: など) を除いた書式名
+	formatName := format
+	if i := strings.LastIndex(format, ":"); i >= 0 {
+		formatName = format[i+1:]
+	}
+
+	switch formatName {
+	case "zerodash", "fixed-zero", "fixedzero":
+		return "0", nil
+	case "numcommadecimal", "num-comma-decimal":
+		text = strings.ReplaceAll(text, ".", "")
+		text = strings.ReplaceAll(text, " ", "")
+		text = strings.ReplaceAll(text, ",", ".")
+	default:
+		// numdotdecimal, num-dot-decimal など
+		text = strings.ReplaceAll(text, ",", "")
+		text = strings.ReplaceAll(text, " ", "")
+	}
+
+	value, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return "", fmt.Errorf("数値に変換できません: %q", text)
+	}
+
+	if scale != "" {
+		scaleInt, err := strconv.Atoi(scale)
+		if err != nil {
+			return "", fmt.Errorf("scale が不正です: %q", scale)
+		}
+		multiplier := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(absInt(scaleInt))), nil))
+		if scaleInt >= 0 {
+			value.Mul(value, multiplier)
+		} else {
+			value.Quo(value, multiplier)
+		}
+	}
+
+	if sign == "-" {
+		value.Neg(value)
+	}
+
+	if value.IsInt() {
+		return value.Num().String(), nil
+	}
+	return strings.TrimRight(strings.TrimRight(value.FloatString(10), "0"), "."), nil
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+/*
+XBRL インスタンスとインライン XBRL の数値を比較し、差異があればログに出力する
+*/
+func CompareIXBRLFacts(docID string, companyName string, body []byte, documents []IXBRLDocument) {
+	instanceFacts, err := ParseInstanceFacts(body)
+	if err != nil {
+		fmt.Println("ParseInstanceFacts error: ", err)
+		return
+	}
+	var ixbrlFacts []Fact
+	for _, document := range documents {
+		facts, err := ParseIXBRLFacts(document.Body)
+		if err != nil {
+			fmt.Printf("%s の ParseIXBRLFacts error (読み込めた部分のみ比較します): %v\n", document.Path, err)
+		}
+		ixbrlFacts = append(ixbrlFacts, facts...)
+	}
+
+	diffs := DiffFacts(instanceFacts, ixbrlFacts)
+	if len(diffs) == 0 {
+		fmt.Printf("「%s」のレポート (%s) の XBRL インスタンスとインライン XBRL の数値は一致しています✅\n", companyName, docID)
+		return
+	}
+	fmt.Printf("「%s」のレポート (%s) の XBRL インスタンスとインライン XBRL で %d 件の数値が異なります❗️\n", companyName, docID, len(diffs))
+	for _, diff := range diffs {
+		fmt.Printf("  %s (%s): XBRL=%s, iXBRL=%s\n", diff.Name, diff.ContextRef, diff.BaseValue, diff.TargetValue)
+	}
+}
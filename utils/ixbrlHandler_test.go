@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+const ixbrlTestHeader = `<html xmlns="http://www.w3.org/1999/xhtml" xmlns:ix="http://www.xbrl.org/2013/inlineXBRL"><body>`
+
+func TestCollectIXBRLTextBlocks(t *testing.T) {
+	documents := []IXBRLDocument{
+		{Path: "0105010_ixbrl.htm", Body: []byte(ixbrlTestHeader +
+			`<ix:nonNumeric name="jpcrp_cor:ConsolidatedBalanceSheetTextBlock" contextRef="CurrentYearDuration" continuedAt="c1"><table><tr><td>資産の部</td></tr></table></ix:nonNumeric>` +
+			`<ix:continuation id="c1" continuedAt="c2"><table><tr><td>負債の部</td></tr></table></ix:continuation>` +
+			`<ix:nonNumeric name="jpcrp_cor:ConsolidatedStatementOfIncomeTextBlock" contextRef="CurrentYearDuration"><table><tr><td>売上高</td></tr></table></ix:nonNumeric>` +
+			`</body></html>`)},
+		// 続きが別のマニュスクリプトにある場合
+		{Path: "0105020_ixbrl.htm", Body: []byte(ixbrlTestHeader +
+			`<ix:continuation id="c2"><table><tr><td>純資産の部</td></tr></table></ix:continuation>` +
+			`<ix:nonNumeric name="jpcrp_cor:ConsolidatedBalanceSheetTextBlock" contextRef="Prior1YearDuration"><table><tr><td>前期</td></tr></table></ix:nonNumeric>` +
+			`</body></html>`)},
+	}
+
+	textBlocks := CollectIXBRLTextBlocks(documents)
+	bs := textBlocks["jpcrp_cor:ConsolidatedBalanceSheetTextBlock"]
+	for _, want := range []string{"資産の部", "負債の部", "純資産の部"} {
+		if !strings.Contains(bs, want) {
+			t.Errorf("BS に %q が含まれていません: %s", want, bs)
+		}
+	}
+	if strings.Index(bs, "資産の部") > strings.Index(bs, "負債の部") || strings.Index(bs, "負債の部") > strings.Index(bs, "純資産の部") {
+		t.Errorf("BS の結合順が不正です: %s", bs)
+	}
+	if strings.Contains(bs, "前期") {
+		t.Errorf("CurrentYearDuration 以外の TextBlock が含まれています: %s", bs)
+	}
+	if pl := textBlocks["jpcrp_cor:ConsolidatedStatementOfIncomeTextBlock"]; !strings.Contains(pl, "売上高") {
+		t.Errorf("PL = %q, want 売上高を含む", pl)
+	}
+}
+
+func TestParseIXBRLFactsSkipsInvalidNumber(t *testing.T) {
+	body := []byte(ixbrlTestHeader +
+		`<ix:nonFraction name="jppfs_cor:NetSales" contextRef="CurrentYearDuration" unitRef="JPY" decimals="-6" scale="6" format="ixt:numdotdecimal">1,234</ix:nonFraction>` +
+		`<ix:nonFraction name="jppfs_cor:OperatingIncome" contextRef="CurrentYearDuration" unitRef="JPY" decimals="-6" scale="6" format="ixt:numdotdecimal">※</ix:nonFraction>` +
+		`<ix:nonFraction name="jppfs_cor:OrdinaryIncome" contextRef="CurrentYearDuration" unitRef="JPY" decimals="-6" scale="6" sign="-" format="ixt:numdotdecimal">56</ix:nonFraction>` +
+		`</body></html>`)
+
+	facts, err := ParseIXBRLFacts(body)
+	if err != nil {
+		t.Fatalf("ParseIXBRLFacts error: %v", err)
+	}
+	got := make(map[string]string)
+	for _, fact := range facts {
+		got[fact.Name] = fact.Value
+	}
+	want := map[string]string{
+		"jppfs_cor:NetSales":       "1234000000",
+		"jppfs_cor:OrdinaryIncome": "-56000000",
+	}
+	if len(got) != len(want) {
+		t.Errorf("facts = %v, want %v", got, want)
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("%s = %q, want %q", name, got[name], value)
+		}
+	}
+}
@@ -0,0 +1,457 @@
+package utils
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// EDINET タクソノミの名称リンクベース (jppfs_*_lab.xml, jpigp_*_lab.xml など) を置いたディレクトリ
+var TaxonomyLabelDir string
+
+// サマリーの項目と勘定科目の対応
+type SummaryLabelField struct {
+	Statement  string   // bs, pl, cf
+	Key        string   // サマリーの json フィールド名
+	ElementIDs []string // タクソノミの要素 ID (接頭辞_要素名)
+	Roles      []string // 辞書に使うラベルのロール (空の場合は標準ラベルと合計ラベル)
+	Synonyms   []string // タクソノミにない表記 (タクソノミを読み込まない場合もこの表記で一致させる)
+}
+
+var SummaryLabelFields = []SummaryLabelField{
+	// 貸借対照表 (内訳の行を合計する長期借入金・社債などは UpdateEverySummary で判定する)
+	{
+		Statement:  "bs",
+		Key:        "current_assets",
+		ElementIDs: []string{"jppfs_cor_CurrentAssets", "jpigp_cor_CurrentAssetsIFRS"},
+		Synonyms:   []string{"流動資産合計"},
+	},
+	{
+		Statement:  "bs",
+		Key:        "tangible_assets",
+		ElementIDs: []string{"jppfs_cor_PropertyPlantAndEquipment"},
+		Synonyms:   []string{"有形固定資産合計"},
+	},
+	{
+		Statement:  "bs",
+		Key:        "intangible_assets",
+		ElementIDs: []string{"jppfs_cor_IntangibleAssets"},
+		Synonyms:   []string{"無形固定資産合計"},
+	},
+	{
+		Statement:  "bs",
+		Key:        "investments_and_other_assets",
+		ElementIDs: []string{"jppfs_cor_InvestmentsAndOtherAssets"},
+		Synonyms:   []string{"投資その他の資産合計"},
+	},
+	{
+		Statement:  "bs",
+		Key:        "current_liabilities",
+		ElementIDs: []string{"jppfs_cor_CurrentLiabilities", "jpigp_cor_TotalCurrentLiabilitiesIFRS"},
+		Synonyms:   []string{"流動負債合計"},
+	},
+	{
+		Statement:  "bs",
+		Key:        "fixed_liabilities",
+		ElementIDs: []string{"jppfs_cor_NoncurrentLiabilities"},
+		Synonyms:   []string{"固定負債合計"},
+	},
+	{
+		Statement:  "bs",
+		Key:        "net_assets",
+		ElementIDs: []string{"jppfs_cor_NetAssets", "jpigp_cor_EquityIFRS"},
+		Synonyms:   []string{"純資産合計", "資本合計"},
+	},
+	{
+		Statement:  "bs",
+		Key:        "liabilities",
+		ElementIDs: []string{"jppfs_cor_Liabilities", "jpigp_cor_LiabilitiesIFRS"},
+		Synonyms:   []string{"負債合計"},
+	},
+	{
+		Statement:  "bs",
+		Key:        "total_assets",
+		ElementIDs: []string{"jppfs_cor_Assets", "jpigp_cor_AssetsIFRS"},
+		Synonyms:   []string{"資産合計"},
+	},
+	{
+		Statement:  "bs",
+		Key:        "shareholders_equity",
+		ElementIDs: []string{"jppfs_cor_ShareholdersEquity"},
+		Synonyms:   []string{"株主資本合計"},
+	},
+	{
+		Statement:  "bs",
+		Key:        "retained_earnings",
+		ElementIDs: []string{"jppfs_cor_RetainedEarnings", "jpigp_cor_RetainedEarningsIFRS"},
+		Synonyms:   []string{"利益剰余金", "利益剰余金合計"},
+	},
+	{
+		Statement:  "bs",
+		Key:        "treasury_stock",
+		ElementIDs: []string{"jppfs_cor_TreasuryStock", "jpigp_cor_TreasurySharesIFRS"},
+		Synonyms:   []string{"自己株式"},
+	},
+	{
+		Statement:  "bs",
+		Key:        "non_controlling_interests",
+		ElementIDs: []string{"jppfs_cor_NonControllingInterests", "jpigp_cor_NonControllingInterestsIFRS"},
+		Synonyms:   []string{"非支配株主持分", "非支配持分"},
+	},
+	{
+		Statement:  "bs",
+		Key:        "subscription_rights",
+		ElementIDs: []string{"jppfs_cor_SubscriptionRightsToShares"},
+		Synonyms:   []string{"新株予約権"},
+	},
+	{
+		Statement:  "bs",
+		Key:        "owners_equity",
+		ElementIDs: []string{"jpigp_cor_EquityAttributableToOwnersOfParentIFRS"},
+		Synonyms:   []string{"親会社の所有者に帰属する持分合計"},
+	},
+	{
+		Statement:  "bs",
+		Key:        "cash_and_deposits",
+		ElementIDs: []string{"jppfs_cor_CashAndDeposits", "jpigp_cor_CashAndCashEquivalentsIFRS"},
+		Synonyms:   []string{"現金及び預金", "現金及び現金同等物"},
+	},
+	{
+		Statement:  "bs",
+		Key:        "inventories",
+		ElementIDs: []string{"jppfs_cor_Inventories", "jpigp_cor_InventoriesIFRS"},
+		Synonyms:   []string{"棚卸資産"},
+	},
+	{
+		Statement:  "bs",
+		Key:        "short_term_borrowings",
+		ElementIDs: []string{"jppfs_cor_ShortTermLoansPayable"},
+		Synonyms:   []string{"短期借入金"},
+	},
+	// 損益計算書
+	{
+		Statement:  "pl",
+		Key:        "sales",
+		ElementIDs: []string{"jppfs_cor_NetSales"},
+		Synonyms:   []string{"売上高", "売上高合計", "純売上高"},
+	},
+	{
+		Statement:  "pl",
+		Key:        "cost_of_goods_sold",
+		ElementIDs: []string{"jppfs_cor_CostOfSales", "jpigp_cor_CostOfSalesIFRS"},
+		Synonyms:   []string{"売上原価", "売上原価合計"},
+	},
+	{
+		Statement:  "pl",
+		Key:        "sg_and_a",
+		ElementIDs: []string{"jppfs_cor_SellingGeneralAndAdministrativeExpenses", "jpigp_cor_SellingGeneralAndAdministrativeExpensesIFRS"},
+		Synonyms:   []string{"販売費及び一般管理費", "販売費及び一般管理費合計", "販売費・一般管理費"},
+	},
+	{
+		Statement:  "pl",
+		Key:        "operating_profit",
+		ElementIDs: []string{"jppfs_cor_OperatingIncome", "jpigp_cor_OperatingProfitLossIFRS"},
+		Synonyms:   []string{"営業利益", "営業損失", "営業利益又は営業損失", "営業損益"},
+	},
+	{
+		Statement:  "pl",
+		Key:        "operating_revenue",
+		ElementIDs: []string{"jppfs_cor_OperatingRevenue1", "jppfs_cor_OperatingRevenue2", "jpigp_cor_RevenueIFRS"},
+		Synonyms:   []string{"営業収益", "営業収益合計", "売上収益", "売上収益合計"},
+	},
+	{
+		Statement:  "pl",
+		Key:        "operating_cost",
+		ElementIDs: []string{"jppfs_cor_OperatingExpenses"},
+		Synonyms:   []string{"営業費用", "営業費用合計"},
+	},
+	{
+		Statement:  "pl",
+		Key:        "ordinary_profit",
+		ElementIDs: []string{"jppfs_cor_OrdinaryIncome"},
+		Synonyms:   []string{"経常利益", "経常損失", "経常利益又は経常損失", "経常損益"},
+	},
+	// キャッシュ・フロー計算書
+	{
+		Statement:  "cf",
+		Key:        "operating_cf",
+		ElementIDs: []string{"jppfs_cor_NetCashProvidedByUsedInOperatingActivities", "jpigp_cor_NetCashProvidedByUsedInOperatingActivitiesIFRS"},
+		Synonyms:   []string{"営業活動によるキャッシュ・フロー", "営業活動による正味キャッシュ・フロー"},
+	},
+	{
+		Statement:  "cf",
+		Key:        "investing_cf",
+		ElementIDs: []string{"jppfs_cor_NetCashProvidedByUsedInInvestmentActivities", "jpigp_cor_NetCashProvidedByUsedInInvestingActivitiesIFRS"},
+		Synonyms:   []string{"投資活動によるキャッシュ・フロー", "投資活動による正味キャッシュ・フロー"},
+	},
+	{
+		Statement:  "cf",
+		Key:        "financing_cf",
+		ElementIDs: []string{"jppfs_cor_NetCashProvidedByUsedInFinancingActivities", "jpigp_cor_NetCashProvidedByUsedInFinancingActivitiesIFRS"},
+		Synonyms:   []string{"財務活動によるキャッシュ・フロー", "財務活動による正味キャッシュ・フロー"},
+	},
+	{
+		Statement:  "cf",
+		Key:        "start_cash",
+		ElementIDs: []string{"jppfs_cor_CashAndCashEquivalents", "jpigp_cor_CashAndCashEquivalentsIFRS"},
+		Roles:      []string{PeriodStartLabelRole},
+		Synonyms:   []string{"現金及び現金同等物の期首残高", "現金及び現金同等物期首残高"},
+	},
+	{
+		Statement:  "cf",
+		Key:        "end_cash",
+		ElementIDs: []string{"jppfs_cor_CashAndCashEquivalents", "jpigp_cor_CashAndCashEquivalentsIFRS"},
+		Roles:      []string{PeriodEndLabelRole},
+		Synonyms:   []string{"現金及び現金同等物の期末残高", "現金及び現金同等物期末残高"},
+	},
+}
+
+// 勘定科目の辞書
+type LabelDictionary struct {
+	// 財務諸表の種類 → 正規化したラベル → サマリーの項目
+	labels map[string]map[string]string
+}
+
+var (
+	summaryLabelDictionary     *LabelDictionary
+	summaryLabelDictionaryOnce sync.Once
+	// TAXONOMY_LABEL_DIR から読み込んだラベル (要素 ID → ロール → ラベル)
+	taxonomyDirLabels map[string]map[string]string
+)
+
+// （△は損失）などの符号の説明
+var signNoteRe = regexp.MustCompile(`（[^（）]*[△▲][^（）]*）`)
+
+// 全角の注記番号 (例: ※１)
+var fullWidthFootnoteRe = regexp.MustCompile(`※[０-９]+`)
+
+// 行のインデント (例: margin-left: 10px)
+var indentStyleRe = regexp.MustCompile(`(?:margin-left|padding-left|text-indent)\s*:\s*([0-9.]+)\s*(px|pt|em)?`)
+
+/*
+サマリー用の勘定科目の辞書を返す (Synonyms と TAXONOMY_LABEL_DIR のラベル)
+TAXONOMY_LABEL_DIR が設定されている場合は、初回呼び出し時にタクソノミの名称リンクベースのラベルを辞書に加える
+*/
+func SummaryLabelDictionary() *LabelDictionary {
+	summaryLabelDictionaryOnce.Do(func() {
+		if TaxonomyLabelDir != "" {
+			var err error
+			taxonomyDirLabels, err = LoadTaxonomyLabels(TaxonomyLabelDir)
+			if err != nil {
+				fmt.Println("タクソノミの名称リンクベースの読み込みエラー❗️: ", err)
+			} else {
+				fmt.Printf("📖 タクソノミの名称リンクベースを読み込みました (%d 要素)\n", len(taxonomyDirLabels))
+			}
+		}
+		summaryLabelDictionary = NewLabelDictionary(SummaryLabelFields, taxonomyDirLabels)
+	})
+	return summaryLabelDictionary
+}
+
+/*
+書類ごとの勘定科目の辞書を返す
+書類の ZIP 内の名称リンクベース (*_lab.xml) には、提出者が標準の要素に付けたラベル (例: NetSales を「営業収益」と表示) があるため、
+SummaryLabelDictionary に加えてそのラベルも辞書に加える
+名称リンクベースがない場合は SummaryLabelDictionary を返す
+*/
+func FilingLabelDictionary(labelFiles []ArchiveFile) *LabelDictionary {
+	dictionary := SummaryLabelDictionary()
+	filingLabels := make(map[string]map[string]string)
+	for _, file := range labelFiles {
+		if !strings.HasSuffix(filepath.Base(file.Path), "_lab.xml") {
+			continue
+		}
+		fileLabels, err := ParseLabelLinkbaseRoles(file.Body, "ja")
+		if err != nil {
+			fmt.Printf("%s の名称リンクベースのパースエラー: %v\n", file.Path, err)
+			continue
+		}
+		mergeRoleLabels(filingLabels, fileLabels)
+	}
+	if len(filingLabels) == 0 {
+		return dictionary
+	}
+	return NewLabelDictionary(SummaryLabelFields, taxonomyDirLabels, filingLabels)
+}
+
+/*
+勘定科目の辞書を作成する
+同じラベルが複数の項目にある場合は先に定義した項目を使う
+@params
+
+	taxonomyLabels: 要素 ID → ロール → ラベル (タクソノミ・提出者の名称リンクベースごと。ない場合は Synonyms のみ使う)
+*/
+func NewLabelDictionary(fields []SummaryLabelField, taxonomyLabels ...map[string]map[string]string) *LabelDictionary {
+	dictionary := &LabelDictionary{labels: make(map[string]map[string]string)}
+	for _, field := range fields {
+		for _, synonym := range field.Synonyms {
+			dictionary.add(field.Statement, synonym, field.Key)
+		}
+		roles := field.Roles
+		if len(roles) == 0 {
+			roles = []string{StandardLabelRole, TotalLabelRole}
+		}
+		for _, labels := range taxonomyLabels {
+			for _, elementID := range field.ElementIDs {
+				for _, role := range roles {
+					if label, ok := labels[elementID][role]; ok {
+						dictionary.add(field.Statement, label, field.Key)
+					}
+				}
+			}
+		}
+	}
+	return dictionary
+}
+
+func (d *LabelDictionary) add(statement string, label string, key string) {
+	normalizedLabel := NormalizeLabel(label)
+	if normalizedLabel == "" {
+		return
+	}
+	if d.labels[statement] == nil {
+		d.labels[statement] = make(map[string]string)
+	}
+	if _, exists := d.labels[statement][normalizedLabel]; !exists {
+		d.labels[statement][normalizedLabel] = key
+	}
+}
+
+/*
+勘定科目 (表の 1 列目) と完全一致するサマリーの項目を返す
+空白・注記番号・符号の説明は除いて比較する
+*/
+func (d *LabelDictionary) Match(statement string, titleName string) (string, bool) {
+	key, ok := d.labels[statement][NormalizeLabel(titleName)]
+	return key, ok
+}
+
+/*
+勘定科目を比較用に正規化する
+例: "営業利益又は営業損失（△）" → "営業利益又は営業損失", "※1 売上原価" → "売上原価", "※1,※2 売上原価" → "売上原価"
+*/
+func NormalizeLabel(label string) string {
+	label = removeSpaces(label)
+	label = AsteriskAndHalfWidthNumRe.ReplaceAllString(label, "")
+	label = fullWidthFootnoteRe.ReplaceAllString(label, "")
+	// 複数の注記番号の区切り (例: ※1,※2)
+	label = strings.TrimLeft(label, ",，、")
+	label = strings.NewReplacer("(", "（", ")", "）").Replace(label)
+	label = signNoteRe.ReplaceAllString(label, "")
+	return label
+}
+
+/*
+TAXONOMY_LABEL_DIR 配下の日本語の名称リンクベース (*_lab.xml) をすべて読み込む
+@returns
+
+	要素 ID → ロール → ラベル
+*/
+func LoadTaxonomyLabels(dir string) (map[string]map[string]string, error) {
+	labels := make(map[string]map[string]string)
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), "_lab.xml") {
+			return nil
+		}
+		body, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		fileLabels, err := ParseLabelLinkbaseRoles(body, "ja")
+		if err != nil {
+			fmt.Printf("%s の名称リンクベースのパースエラー: %v\n", path, err)
+			return nil
+		}
+		mergeRoleLabels(labels, fileLabels)
+		return nil
+	})
+	return labels, err
+}
+
+// 要素 ID → ロール → ラベル のマップに別のファイルのラベルを加える
+func mergeRoleLabels(labels map[string]map[string]string, fileLabels map[string]map[string]string) {
+	for elementID, roles := range fileLabels {
+		if labels[elementID] == nil {
+			labels[elementID] = make(map[string]string)
+		}
+		for role, label := range roles {
+			labels[elementID][role] = label
+		}
+	}
+}
+
+// 一致した行の階層
+type labelMatch struct {
+	indent  int
+	isTotal bool
+}
+
+// 項目ごとに一致した行を記録し、小計・内訳の行で上書きしないようにする
+type LabelMatchState map[string]labelMatch
+
+/*
+一致した行を項目に設定するかどうか
+未設定の場合は設定し、設定済みの場合は合計行もしくはより浅い階層の行のみで上書きする
+(例: 「営業収益」の後に「営業収益合計」がある場合は上書きし、合計行の後の内訳の行では上書きしない)
+*/
+func (m LabelMatchState) Accept(key string, indent int, titleName string) bool {
+	match := labelMatch{
+		indent:  indent,
+		isTotal: strings.HasSuffix(NormalizeLabel(titleName), "合計"),
+	}
+	previous, exists := m[key]
+	accepted := !exists
+	if exists && previous.isTotal {
+		accepted = match.isTotal && match.indent < previous.indent
+	} else if exists {
+		accepted = match.isTotal || match.indent < previous.indent
+	}
+	if accepted {
+		m[key] = match
+	}
+	return accepted
+}
+
+/*
+行のインデント (px) を取得する
+1 列目の td とその中の要素の style (margin-left, padding-left, text-indent) と、先頭の全角スペースから求める
+*/
+func RowIndent(tr *goquery.Selection) int {
+	td := tr.Find("td").First()
+	indent := 0.0
+	td.Find("*").AddSelection(td).Each(func(i int, s *goquery.Selection) {
+		style, ok := s.Attr("style")
+		if !ok {
+			return
+		}
+		for _, match := range indentStyleRe.FindAllStringSubmatch(style, -1) {
+			value, err := strconv.ParseFloat(match[1], 64)
+			if err != nil {
+				continue
+			}
+			switch match[2] {
+			case "pt":
+				value = value * 4 / 3
+			case "em":
+				value = value * 16
+			}
+			indent += value
+		}
+	})
+	text := strings.TrimLeft(td.Text(), " \n\t")
+	for strings.HasPrefix(text, "　") {
+		indent += 16
+		text = strings.TrimPrefix(text, "　")
+	}
+	return int(indent)
+}
@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestNormalizeLabel(t *testing.T) {
+	tests := []struct {
+		label string
+		want  string
+	}{
+		{"売上高", "売上高"},
+		{"　売上高 ", "売上高"},
+		{"営業利益又は営業損失（△）", "営業利益又は営業損失"},
+		{"営業利益又は営業損失(△)", "営業利益又は営業損失"},
+		{"当期純利益（△は損失）", "当期純利益"},
+		{"※1 売上原価", "売上原価"},
+		{"※１　売上原価", "売上原価"},
+		{"※1,※2 販売費及び一般管理費", "販売費及び一般管理費"},
+		// 符号の説明以外の括弧は残す
+		{"現金及び現金同等物（連結）", "現金及び現金同等物（連結）"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeLabel(tt.label); got != tt.want {
+			t.Errorf("NormalizeLabel(%q) = %q, want %q", tt.label, got, tt.want)
+		}
+	}
+}
+
+func TestLabelMatchStateAccept(t *testing.T) {
+	type row struct {
+		indent    int
+		titleName string
+		want      bool
+	}
+	tests := []struct {
+		name string
+		rows []row
+	}{
+		{
+			name: "小計の後の合計行で上書きする",
+			rows: []row{
+				{16, "営業収益", true},
+				{0, "営業収益合計", true},
+			},
+		},
+		{
+			name: "合計行の後の内訳の行では上書きしない",
+			rows: []row{
+				{0, "営業収益合計", true},
+				{16, "営業収益", false},
+			},
+		},
+		{
+			name: "深い階層の行では上書きしない",
+			rows: []row{
+				{0, "営業収益", true},
+				{32, "営業収益", false},
+			},
+		},
+		{
+			name: "浅い階層の行で上書きする",
+			rows: []row{
+				{32, "営業収益", true},
+				{0, "営業収益", true},
+			},
+		},
+		{
+			name: "合計行は浅い階層の合計行でのみ上書きする",
+			rows: []row{
+				{16, "営業収益合計", true},
+				{16, "営業収益合計", false},
+				{0, "営業収益", false},
+				{0, "営業収益合計", true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := LabelMatchState{}
+			for i, r := range tt.rows {
+				if got := state.Accept("operating_revenue", r.indent, r.titleName); got != r.want {
+					t.Errorf("%d 行目 Accept(%d, %q) = %v, want %v", i+1, r.indent, r.titleName, got, r.want)
+				}
+			}
+		})
+	}
+
+	t.Run("項目ごとに判定する", func(t *testing.T) {
+		state := LabelMatchState{}
+		state.Accept("sales", 0, "売上高合計")
+		if !state.Accept("operating_profit", 16, "営業利益") {
+			t.Error("別の項目の合計行の影響を受けています")
+		}
+	})
+}
+
+func TestRowIndent(t *testing.T) {
+	tests := []struct {
+		name string
+		tr   string
+		want int
+	}{
+		{"インデントなし", `<tr><td><p>売上高</p></td><td>1</td></tr>`, 0},
+		{"td の padding-left", `<tr><td style="padding-left: 10px"><p>売上高</p></td></tr>`, 10},
+		{"p の margin-left (pt)", `<tr><td><p style="margin-left:12pt">売上高</p></td></tr>`, 16},
+		{"p の text-indent (em)", `<tr><td><p style="text-indent: 1em">売上高</p></td></tr>`, 16},
+		{"td と p の合計", `<tr><td style="padding-left:5px"><p style="margin-left:10px">売上高</p></td></tr>`, 15},
+		{"先頭の全角スペース", `<tr><td><p>　　売上高</p></td></tr>`, 32},
+		{"2 列目のスタイルは対象外", `<tr><td><p>売上高</p></td><td style="padding-left:20px">1</td></tr>`, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader("<table>" + tt.tr + "</table>"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := RowIndent(doc.Find("tr").First()); got != tt.want {
+				t.Errorf("RowIndent() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// 提出者が標準の要素 (売上高) に独自のラベルを付けた名称リンクベース
+const filingLabelLinkbase = `<?xml version="1.0" encoding="UTF-8"?>
+<link:linkbase xmlns:link="http://www.xbrl.org/2003/linkbase" xmlns:xlink="http://www.w3.org/1999/xlink" xmlns:xml="http://www.w3.org/XML/1998/namespace">
+  <link:labelLink xlink:type="extended" xlink:role="http://www.xbrl.org/2003/role/link">
+    <link:loc xlink:type="locator" xlink:href="http://disclosure.edinet-fsa.go.jp/taxonomy/jppfs/2023-12-01/jppfs_cor_2023-12-01.xsd#jppfs_cor_NetSales" xlink:label="NetSales"/>
+    <link:label xlink:type="resource" xlink:label="label_NetSales" xlink:role="http://www.xbrl.org/2003/role/label" xml:lang="ja">売上高及び営業収益</link:label>
+    <link:labelArc xlink:type="arc" xlink:arcrole="http://www.xbrl.org/2003/arcrole/concept-label" xlink:from="NetSales" xlink:to="label_NetSales"/>
+  </link:labelLink>
+</link:linkbase>`
+
+func TestFilingLabelDictionary(t *testing.T) {
+	labelFiles := []ArchiveFile{{Path: "XBRL/PublicDoc/jpcrp030000-asr-001_E00001-000_2024-03-31_01_2024-06-20_lab.xml", Body: []byte(filingLabelLinkbase)}}
+
+	if _, ok := FilingLabelDictionary(nil).Match("pl", "売上高及び営業収益"); ok {
+		t.Error("名称リンクベースがない場合に提出者のラベルと一致しています")
+	}
+	dictionary := FilingLabelDictionary(labelFiles)
+	tests := []struct {
+		statement string
+		titleName string
+		wantKey   string
+	}{
+		{"pl", "売上高及び営業収益", "sales"},
+		{"pl", "※1 売上高及び営業収益", "sales"},
+		// Synonyms も使う
+		{"pl", "売上高", "sales"},
+		{"pl", "営業利益又は営業損失（△）", "operating_profit"},
+		{"cf", "現金及び現金同等物の期末残高", "end_cash"},
+	}
+	for _, tt := range tests {
+		key, ok := dictionary.Match(tt.statement, tt.titleName)
+		if !ok || key != tt.wantKey {
+			t.Errorf("Match(%q, %q) = %q, %v, want %q", tt.statement, tt.titleName, key, ok, tt.wantKey)
+		}
+	}
+	if _, ok := dictionary.Match("bs", "売上高及び営業収益"); ok {
+		t.Error("PL のラベルが BS と一致しています")
+	}
+}
@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ラベルのロール
+const (
+	StandardLabelRole    = "http://www.xbrl.org/2003/role/label"
+	TotalLabelRole       = "http://www.xbrl.org/2003/role/totalLabel"
+	PeriodStartLabelRole = "http://www.xbrl.org/2003/role/periodStartLabel"
+	PeriodEndLabelRole   = "http://www.xbrl.org/2003/role/periodEndLabel"
+)
+
+// 名称リンクベースの xlink 属性
+type labelLinkAttrs struct {
+	Href  string
+	Label string
+	From  string
+	To    string
+	Role  string
+	Lang  string
+}
+
+/*
+名称リンクベース (*_lab.xml) から要素 ID (例: jpcrp030000-asr_E00001-000_AutomobileReportableSegmentsMember) ごとのラベルを取得する
+標準ラベルを優先し、標準ラベルがない要素はそれ以外のロールのラベルを使う
+@params
+
+	lang: xml:lang で絞り込む言語 (空文字の場合は絞り込まない)
+*/
+func ParseLabelLinkbase(body []byte, lang string) (map[string]string, error) {
+	roleLabels, err := ParseLabelLinkbaseRoles(body, lang)
+	if err != nil {
+		return nil, err
+	}
+	labels := make(map[string]string)
+	for elementID, roles := range roleLabels {
+		if label, ok := roles[StandardLabelRole]; ok {
+			labels[elementID] = label
+			continue
+		}
+		// 標準ラベルがない場合はロール名の順で最初のラベルを使う
+		for _, role := range slices.Sorted(maps.Keys(roles)) {
+			labels[elementID] = roles[role]
+			break
+		}
+	}
+	return labels, nil
+}
+
+/*
+名称リンクベースから要素 ID ごとにロール別のラベルを取得する
+@returns
+
+	要素 ID → ロール → ラベル
+*/
+func ParseLabelLinkbaseRoles(body []byte, lang string) (map[string]map[string]string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+
+	// xlink:label → 要素 ID
+	locs := make(map[string]string)
+	// xlink:label → ラベル (ロールごと)
+	type labelResource struct {
+		role string
+		text string
+	}
+	resources := make(map[string][]labelResource)
+	// xlink:from → xlink:to
+	arcs := make(map[string][]string)
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		t, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		attrs := readLabelLinkAttrs(t.Attr)
+		switch t.Name.Local {
+		case "loc":
+			if i := strings.LastIndex(attrs.Href, "#"); i >= 0 {
+				locs[attrs.Label] = attrs.Href[i+1:]
+			}
+		case "label":
+			var text string
+			err := decoder.DecodeElement(&text, &t)
+			if err != nil {
+				return nil, err
+			}
+			if lang != "" && attrs.Lang != "" && attrs.Lang != lang {
+				continue
+			}
+			resources[attrs.Label] = append(resources[attrs.Label], labelResource{
+				role: attrs.Role,
+				text: strings.TrimSpace(text),
+			})
+		case "labelArc":
+			arcs[attrs.From] = append(arcs[attrs.From], attrs.To)
+		}
+	}
+
+	labels := make(map[string]map[string]string)
+	for locLabel, elementID := range locs {
+		for _, to := range arcs[locLabel] {
+			for _, resource := range resources[to] {
+				if resource.text == "" {
+					continue
+				}
+				if labels[elementID] == nil {
+					labels[elementID] = make(map[string]string)
+				}
+				labels[elementID][resource.role] = resource.text
+			}
+		}
+	}
+	return labels, nil
+}
+
+func readLabelLinkAttrs(attrs []xml.Attr) labelLinkAttrs {
+	var result labelLinkAttrs
+	for _, attr := range attrs {
+		switch attr.Name.Local {
+		case "href":
+			result.Href = attr.Value
+		case "label":
+			result.Label = attr.Value
+		case "from":
+			result.From = attr.Value
+		case "to":
+			result.To = attr.Value
+		case "role":
+			result.Role = attr.Value
+		case "lang":
+			result.Lang = attr.Value
+		}
+	}
+	return result
+}
+
+/*
+ZIP から取得した名称リンクベースのうち、指定した言語のファイルのラベルをまとめる
+日本語は *_lab.xml, 英語は *_lab-en.xml を使う
+*/
+func CreateLabelMap(labelFiles []ArchiveFile, lang string) map[string]string {
+	suffix := "_lab.xml"
+	if lang == "en" {
+		suffix = "_lab-en.xml"
+	}
+	labels := make(map[string]string)
+	for _, file := range labelFiles {
+		if !strings.HasSuffix(filepath.Base(file.Path), suffix) {
+			continue
+		}
+		fileLabels, err := ParseLabelLinkbase(file.Body, lang)
+		if err != nil {
+			continue
+		}
+		for elementID, label := range fileLabels {
+			labels[elementID] = label
+		}
+	}
+	return labels
+}
+
+// 「接頭辞:要素名」を名称リンクベースの要素 ID (接頭辞_要素名) に変換する
+func LabelElementID(qualifiedName string) string {
+	return strings.Replace(strings.TrimSpace(qualifiedName), ":", "_", 1)
+}
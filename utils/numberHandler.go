@@ -0,0 +1,179 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 数値として解釈できない文字列の場合のエラー
+var ErrInvalidJPNumber = errors.New("数値ではありません")
+
+// 空欄の場合のエラー
+var ErrEmptyJPNumber = errors.New("空欄です")
+
+// 小数を整数に変換しようとした場合のエラー
+var ErrDecimalJPNumber = errors.New("小数は整数に変換できません")
+
+// 財務諸表の数値 (決算書の表記を解釈した値)
+type JPNumber struct {
+	Amount    int64    // 金額 (小数の場合は整数部)
+	Decimal   float64  // 小数を含む値 (整数の場合も Amount と同じ値)
+	IsDecimal bool     // 小数を含むかどうか
+	IsNil     bool     // 空欄
+	IsDash    bool     // －, ― など 0 を表す記号
+	Footnotes []string // 注記番号 (例: ※1 → "1")
+}
+
+// 全角の数字・記号を半角にする
+var jpNumberReplacer = strings.NewReplacer(
+	"０", "0", "１", "1", "２", "2", "３", "3", "４", "4",
+	"５", "5", "６", "6", "７", "7", "８", "8", "９", "9",
+	"，", ",", "．", ".", "（", "(", "）", ")", "＊", "*",
+	"　", " ", " ", " ",
+)
+
+// 注記の記号 (例: ※1, *1)
+var footnoteMarkRe = regexp.MustCompile(`[※*]\s?([0-9]{1,2})`)
+
+// 0 を表す記号
+var jpNumberDashes = []string{"-", "－", "―", "‐", "—", "−", "ー"}
+
+// 負の数を表す記号
+var jpNumberMinusSigns = []string{"△", "▲", "-", "－", "−"}
+
+// 末尾の単位
+var jpNumberUnits = []string{"百万円", "千円", "円", "千株", "株", "口", "％", "%"}
+
+var jpNumberRe = regexp.MustCompile(`^[0-9]{1,3}(?:,[0-9]{3})*(?:\.[0-9]+)?$|^[0-9]+(?:\.[0-9]+)?$`)
+
+/*
+財務諸表の数値の文字列を解釈する
+正常系
+
+	10,897,603 / △1,234 / ▲1,234 / (1,234) / －1,234 / １，２３４ / 12.34
+	※1 10,897,603 / ※1,※2 10,897,603 / ※１ 10,897,603
+	－ / ― (0) / 空文字 (IsNil)
+
+異常系
+
+	売上高 / 2023年4月1日 など数値以外の文字を含むもの
+*/
+func ParseJPNumber(text string) (JPNumber, error) {
+	var number JPNumber
+	text = jpNumberReplacer.Replace(text)
+
+	// 注記番号
+	text, number.Footnotes = extractFootnotes(text)
+	text = strings.Join(strings.Fields(text), "")
+	for _, unit := range jpNumberUnits {
+		if strings.HasSuffix(text, unit) && len(text) > len(unit) {
+			text = strings.TrimSuffix(text, unit)
+			break
+		}
+	}
+
+	if text == "" {
+		number.IsNil = true
+		return number, nil
+	}
+	if isDashOnly(text) {
+		number.IsDash = true
+		return number, nil
+	}
+
+	// 符号
+	isMinus := false
+	if strings.HasPrefix(text, "(") && strings.HasSuffix(text, ")") {
+		isMinus = true
+		text = text[1 : len(text)-1]
+	}
+	for _, sign := range jpNumberMinusSigns {
+		if strings.HasPrefix(text, sign) {
+			isMinus = !isMinus
+			text = strings.TrimPrefix(text, sign)
+			break
+		}
+	}
+
+	if !jpNumberRe.MatchString(text) {
+		return JPNumber{}, fmt.Errorf("%w: %q", ErrInvalidJPNumber, text)
+	}
+	text = strings.ReplaceAll(text, ",", "")
+
+	integerText, _, hasDecimal := strings.Cut(text, ".")
+	amount, err := strconv.ParseInt(integerText, 10, 64)
+	if err != nil {
+		return JPNumber{}, fmt.Errorf("%w: %v", ErrInvalidJPNumber, err)
+	}
+	decimal, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return JPNumber{}, fmt.Errorf("%w: %v", ErrInvalidJPNumber, err)
+	}
+	if isMinus {
+		amount = -amount
+		decimal = -decimal
+	}
+	number.Amount = amount
+	number.Decimal = decimal
+	number.IsDecimal = hasDecimal
+	return number, nil
+}
+
+/*
+int の範囲の金額を返す
+小数の場合 (ErrDecimalJPNumber) と 32 ビット環境で int に収まらない場合はエラーを返す
+小数の値は Decimal を使う
+*/
+func (n JPNumber) Int() (int, error) {
+	if n.IsDecimal {
+		return 0, fmt.Errorf("%w: %v", ErrDecimalJPNumber, n.Decimal)
+	}
+	value := int(n.Amount)
+	if int64(value) != n.Amount {
+		return 0, fmt.Errorf("%d は int の範囲外です", n.Amount)
+	}
+	return value, nil
+}
+
+// 注記の記号と番号を取り除き、番号を返す (例: "※1,※2 1,234" → " 1,234", ["1", "2"])
+func extractFootnotes(text string) (string, []string) {
+	var footnotes []string
+	var builder strings.Builder
+	rest := text
+	for {
+		loc := footnoteMarkRe.FindStringSubmatchIndex(rest)
+		if loc == nil {
+			builder.WriteString(rest)
+			break
+		}
+		// 番号の直後に数字が続く場合 (例: ※11,234) は注記番号と金額を区別できないため注記として扱わない
+		after := rest[loc[1]:]
+		if after != "" && (after[0] >= '0' && after[0] <= '9' || after[0] == ',' && len(after) > 1 && after[1] >= '0' && after[1] <= '9') {
+			builder.WriteString(rest[:loc[1]])
+			rest = after
+			continue
+		}
+		builder.WriteString(rest[:loc[0]])
+		footnotes = append(footnotes, rest[loc[2]:loc[3]])
+		// 注記の区切り (例: ※1,※2)
+		rest = strings.TrimLeft(after, ",、")
+	}
+	return builder.String(), footnotes
+}
+
+func isDashOnly(text string) bool {
+	for text != "" {
+		trimmed := text
+		for _, dash := range jpNumberDashes {
+			trimmed = strings.TrimPrefix(trimmed, dash)
+		}
+		if trimmed == text {
+			return false
+		}
+		text = trimmed
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParseJPNumber(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want JPNumber
+	}{
+		{"カンマ区切り", "10,897,603", JPNumber{Amount: 10897603, Decimal: 10897603}},
+		{"カンマなし", "1234", JPNumber{Amount: 1234, Decimal: 1234}},
+		{"前後の空白", "\n  1,234 \n", JPNumber{Amount: 1234, Decimal: 1234}},
+		{"ゼロ", "0", JPNumber{}},
+		{"△ は負の数", "△1,234", JPNumber{Amount: -1234, Decimal: -1234}},
+		{"▲ は負の数", "▲1,234", JPNumber{Amount: -1234, Decimal: -1234}},
+		{"半角マイナス", "-1,234", JPNumber{Amount: -1234, Decimal: -1234}},
+		{"全角マイナス", "－1,234", JPNumber{Amount: -1234, Decimal: -1234}},
+		{"数学記号のマイナス", "−1,234", JPNumber{Amount: -1234, Decimal: -1234}},
+		{"括弧は負の数", "(1,234)", JPNumber{Amount: -1234, Decimal: -1234}},
+		{"全角括弧は負の数", "（1,234）", JPNumber{Amount: -1234, Decimal: -1234}},
+		{"△ と数値の間の空白", "△ 1,234", JPNumber{Amount: -1234, Decimal: -1234}},
+		{"全角数字", "１２，３４５", JPNumber{Amount: 12345, Decimal: 12345}},
+		{"全角の △", "△１，２３４", JPNumber{Amount: -1234, Decimal: -1234}},
+		{"小数", "12.34", JPNumber{Amount: 12, Decimal: 12.34, IsDecimal: true}},
+		{"全角の小数", "１２．３４", JPNumber{Amount: 12, Decimal: 12.34, IsDecimal: true}},
+		{"負の小数", "△0.5", JPNumber{Amount: 0, Decimal: -0.5, IsDecimal: true}},
+		{"カンマ区切りの小数", "1,234.56", JPNumber{Amount: 1234, Decimal: 1234.56, IsDecimal: true}},
+		{"int32 を超える値", "12,345,678,901", JPNumber{Amount: 12345678901, Decimal: 12345678901}},
+		{"int32 を超える負の値", "△3,000,000,000", JPNumber{Amount: -3000000000, Decimal: -3000000000}},
+		{"空文字", "", JPNumber{IsNil: true}},
+		{"空白のみ", " \n　", JPNumber{IsNil: true}},
+		{"半角ハイフン", "-", JPNumber{IsDash: true}},
+		{"全角ハイフン", "－", JPNumber{IsDash: true}},
+		{"ダッシュ", "―", JPNumber{IsDash: true}},
+		{"長音記号", "ー", JPNumber{IsDash: true}},
+		{"連続したダッシュ", "――", JPNumber{IsDash: true}},
+		{"注記番号", "※1 10,897,603", JPNumber{Amount: 10897603, Decimal: 10897603, Footnotes: []string{"1"}}},
+		{"全角の注記番号", "※１ 10,897,603", JPNumber{Amount: 10897603, Decimal: 10897603, Footnotes: []string{"1"}}},
+		{"複数の注記番号", "※1,※2 10,897,603", JPNumber{Amount: 10897603, Decimal: 10897603, Footnotes: []string{"1", "2"}}},
+		{"全角の複数の注記番号", "※１，※２　10,897,603", JPNumber{Amount: 10897603, Decimal: 10897603, Footnotes: []string{"1", "2"}}},
+		{"2 桁の注記番号", "※12 1,234", JPNumber{Amount: 1234, Decimal: 1234, Footnotes: []string{"12"}}},
+		{"注記番号と △", "※3 △1,234", JPNumber{Amount: -1234, Decimal: -1234, Footnotes: []string{"3"}}},
+		{"アスタリスクの注記番号", "*1 1,234", JPNumber{Amount: 1234, Decimal: 1234, Footnotes: []string{"1"}}},
+		{"後ろの注記番号", "1,234 ※2", JPNumber{Amount: 1234, Decimal: 1234, Footnotes: []string{"2"}}},
+		{"注記番号とダッシュ", "※1 ―", JPNumber{IsDash: true, Footnotes: []string{"1"}}},
+		{"注記番号のみ", "※1", JPNumber{IsNil: true, Footnotes: []string{"1"}}},
+		{"単位の円", "1,234円", JPNumber{Amount: 1234, Decimal: 1234}},
+		{"単位の百万円", "1,234百万円", JPNumber{Amount: 1234, Decimal: 1234}},
+		{"単位の株", "1,000株", JPNumber{Amount: 1000, Decimal: 1000}},
+		{"単位の %", "12.5%", JPNumber{Amount: 12, Decimal: 12.5, IsDecimal: true}},
+		{"単位の全角 ％", "12.5％", JPNumber{Amount: 12, Decimal: 12.5, IsDecimal: true}},
+		{"ノーブレークスペース", " 1,234 ", JPNumber{Amount: 1234, Decimal: 1234}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseJPNumber(tt.text)
+			if err != nil {
+				t.Fatalf("ParseJPNumber(%q) error: %v", tt.text, err)
+			}
+			if got.Amount != tt.want.Amount || got.Decimal != tt.want.Decimal || got.IsDecimal != tt.want.IsDecimal ||
+				got.IsNil != tt.want.IsNil || got.IsDash != tt.want.IsDash || !slices.Equal(got.Footnotes, tt.want.Footnotes) {
+				t.Errorf("ParseJPNumber(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJPNumberInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"勘定科目", "売上高"},
+		{"日付", "2023年4月1日"},
+		{"期間の見出し", "(自 2023年4月1日"},
+		{"カンマの位置が不正", "1,23,4"},
+		{"数字の途中の空白以外の文字", "12a34"},
+		{"注記番号と金額の区別がつかない", "※11,234"},
+		{"閉じ括弧のない負の数", "(1,234"},
+		{"小数点のみ", "."},
+		{"int64 を超える値", "99,999,999,999,999,999,999"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseJPNumber(tt.text)
+			if !errors.Is(err, ErrInvalidJPNumber) {
+				t.Errorf("ParseJPNumber(%q) = %+v, %v, want ErrInvalidJPNumber", tt.text, got, err)
+			}
+		})
+	}
+}
+
+func TestConvertTextValue2IntValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    int
+		wantErr error
+	}{
+		{"カンマ区切り", "10,897,603", 10897603, nil},
+		{"△", "△1,234", -1234, nil},
+		{"▲", "▲1,234", -1234, nil},
+		{"全角の注記番号", "※１ 1,234", 1234, nil},
+		{"ダッシュは 0", "―", 0, nil},
+		{"括弧は負の数", "(500)", -500, nil},
+		{"小数は切り捨てない", "1.9", 0, ErrDecimalJPNumber},
+		{"空文字", "", 0, ErrEmptyJPNumber},
+		{"注記番号のみ", "※1", 0, ErrEmptyJPNumber},
+		{"数値以外", "売上高", 0, ErrInvalidJPNumber},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertTextValue2IntValue(tt.text)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ConvertTextValue2IntValue(%q) error = %v, want %v", tt.text, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ConvertTextValue2IntValue(%q) error: %v", tt.text, err)
+			}
+			if got != tt.want {
+				t.Errorf("ConvertTextValue2IntValue(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+		})
+	}
+}
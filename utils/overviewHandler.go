@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+/*
+XBRL インスタンスの DEI と「企業の概況」「従業員の状況」から企業情報を取得する
+従業員数は連結、平均年間給与・平均年齢は提出会社の値を使う
+*/
+func CreateCompanyOverview(facts []Fact) CompanyOverview {
+	var overview CompanyOverview
+	overview.NameEn = FindFactValue(facts, "jpdei_cor:FilerNameInEnglishDEI", "FilingDateInstant")
+	overview.FiscalYearEnd = FindFactValue(facts, "jpdei_cor:CurrentFiscalYearEndDateDEI", "FilingDateInstant")
+	overview.AccountingStandard = FindFactValue(facts, "jpdei_cor:AccountingStandardsDEI", "FilingDateInstant")
+	overview.IsConsolidated = FindFactValue(facts, "jpdei_cor:WhetherConsolidatedFinancialStatementsArePreparedDEI", "FilingDateInstant") == "true"
+	overview.IndustryCode = FindFactValue(facts,
+		"jpdei_cor:IndustryCodeWhenConsolidatedFinancialStatementsArePreparedInAccordanceWithIndustrySpecificRegulationsDEI", "FilingDateInstant")
+	if overview.IndustryCode == "" {
+		overview.IndustryCode = FindFactValue(facts,
+			"jpdei_cor:IndustryCodeWhenFinancialStatementsArePreparedInAccordanceWithIndustrySpecificRegulationsDEI", "FilingDateInstant")
+	}
+	overview.HeadOfficeAddress = FindFactValue(facts, "jpcrp_cor:AddressOfRegisteredHeadquarterCoverPage", "FilingDateInstant")
+	overview.Representative = strings.Join(strings.Fields(FindFactValue(facts, "jpcrp_cor:TitleAndNameOfRepresentativeCoverPage", "FilingDateInstant")), " ")
+
+	employees := FindFactValue(facts, "jpcrp_cor:NumberOfEmployees", "CurrentYearInstant", "CurrentYearInstant_NonConsolidatedMember")
+	overview.Employees, _ = strconv.Atoi(employees)
+	averageSalary := FindFactValue(facts, "jpcrp_cor:AverageAnnualSalaryInformationAboutReportingCompanyInformationAboutEmployees", "CurrentYearInstant_NonConsolidatedMember", "CurrentYearInstant")
+	overview.AverageSalary, _ = strconv.Atoi(averageSalary)
+	averageAge := FindFactValue(facts, "jpcrp_cor:AverageAgeYearsInformationAboutReportingCompanyInformationAboutEmployees", "CurrentYearInstant_NonConsolidatedMember", "CurrentYearInstant")
+	overview.AverageAge, _ = strconv.ParseFloat(averageAge, 64)
+	return overview
+}
+
+/*
+登録済みの企業の概況に、今回の書類から取得できた値のみを上書きする
+訂正報告書など一部の事実がない書類で、登録済みの値を空文字・0 で消さないようにする
+連結財務諸表の有無は DEI (決算日) を取得できた場合のみ上書きする
+*/
+func MergeCompanyOverview(current CompanyOverview, update CompanyOverview) CompanyOverview {
+	merged := current
+	if update.NameEn != "" {
+		merged.NameEn = update.NameEn
+	}
+	if update.FiscalYearEnd != "" {
+		merged.FiscalYearEnd = update.FiscalYearEnd
+		merged.IsConsolidated = update.IsConsolidated
+	}
+	if update.AccountingStandard != "" {
+		merged.AccountingStandard = update.AccountingStandard
+	}
+	if update.Employees != 0 {
+		merged.Employees = update.Employees
+	}
+	if update.AverageSalary != 0 {
+		merged.AverageSalary = update.AverageSalary
+	}
+	if update.AverageAge != 0 {
+		merged.AverageAge = update.AverageAge
+	}
+	if update.HeadOfficeAddress != "" {
+		merged.HeadOfficeAddress = update.HeadOfficeAddress
+	}
+	if update.IndustryCode != "" {
+		merged.IndustryCode = update.IndustryCode
+	}
+	if update.Representative != "" {
+		merged.Representative = update.Representative
+	}
+	return merged
+}
+
+/*
+コンテキストの優先順に事実の値を探す
+見つからない場合は空文字を返す
+*/
+func FindFactValue(facts []Fact, name string, contextRefs ...string) string {
+	for _, contextRef := range contextRefs {
+		fact, ok := FindFact(facts, name, contextRef)
+		if ok && !fact.IsNil {
+			return strings.TrimSpace(fact.Value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+/*
+PDF (type=2) をダウンロードし、EDINET バケットの {dateKey}/{docID}/{docID}.pdf に登録する
+登録後、企業情報に PDF のキーを設定する
+*/
+func RegisterPDF(dynamoClient *dynamodb.Client, client *http.Client, EDINETCode string, docID string, dateKey string, companyName string) {
+	pdfKey := fmt.Sprintf("%s/%s/%s.pdf", dateKey, docID, docID)
+
+	// 登録済みの場合はダウンロードしない
+	existsFile, err := CheckFileExists(S3Client, EDINETBucketName, pdfKey)
+	if err != nil {
+		fmt.Println("PDF の存在チェック時のエラー❗️: ", err)
+	}
+	if !existsFile {
+		ApiTimes += 1
+		pdfBody, err := DownloadDocument(client, docID, 2)
+		if err != nil {
+			ErrMsg = "PDF ダウンロードエラー: "
+			RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+			return
+		}
+		err = PutDocumentToEDINETBucket(pdfKey, pdfBody, "application/pdf")
+		if err != nil {
+			ErrMsg = "S3 への PDF 送信エラー: "
+			RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+			return
+		}
+		fmt.Printf("「%s」の PDF (%s) を登録しました ⭕️\n", companyName, pdfKey)
+	}
+
+	err = UpdatePdfKey(dynamoClient, EDINETCode, pdfKey)
+	if err != nil {
+		ErrMsg = "PDF キー登録エラー: "
+		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+	}
+}
+
+/*
+EDINET バケットに書類を登録する (dry-run の場合はローカルに書き出す)
+*/
+func PutDocumentToEDINETBucket(key string, body []byte, contentType string) error {
+	if DryRun == "true" {
+		return PutDryRunObject(EDINETBucketName, key, body)
+	}
+	_, err := S3Client.PutObject(context.TODO(), &s3.PutObjectInput{
+		Bucket:      aws.String(EDINETBucketName),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String(contentType),
+	})
+	return err
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"archive/zip"
+	"bytes"
 	"context"
 	"encoding/json"
 	"encoding/xml"
@@ -12,8 +13,10 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -51,7 +54,6 @@ var FailedJSONFile = filepath.Join("/tmp", "failed.json")
 var FailedReports []FailedReport
 var Mu sync.Mutex
 var ErrMsg string
-var EmptyStrConvErr = `strconv.Atoi: parsing "": invalid syntax`
 
 // Lambda 用に /tmp を足す
 var InvalidSummaryJSONFile = filepath.Join("/tmp", "invalid-summary.json")
@@ -60,10 +62,13 @@ var ApiTimes int
 var RegisterSingleReport string
 var Env string
 var Parallel string
-var FromToPattern = `\b(BS|CF|PL|fundamentals)-from-\d{4}-\d{2}-\d{2}-to-\d{4}-\d{2}-\d{2}\.(html|json)`
+var FromToPattern = `\b(BS|CF|PL|SS|CI|Audit|CSVDiff|EN|Segments|Shareholders|Governance|Fund|fundamentals)-from-\d{4}-\d{2}-\d{2}-to-\d{4}-\d{2}-\d{2}\.(html|json)`
 var FromToWithoutTypePattern = `-from-\d{4}-\d{2}-\d{2}-to-\d{4}-\d{2}-\d{2}\.(html|json)`
 var XBRLExtensionPattern = `.xbrl`
 
+// 1 資料あたりのメモリ使用量の上限 (ダウンロードした ZIP と解凍後の XBRL それぞれに適用)
+var MaxDocumentBytes int64 = 100 * 1024 * 1024
+
 func init() {
 	Env = os.Getenv("ENV")
 	fmt.Println("環境: ", Env)
@@ -88,7 +93,7 @@ func init() {
 
 	cfg, cfgErr := config.LoadDefaultConfig(context.TODO())
 	if cfgErr != nil {
-		fmt.Println("Load default config error: %v", cfgErr)
+		fmt.Printf("Load default config error: %v\n", cfgErr)
 		return
 	}
 	region := os.Getenv("REGION")
@@ -104,71 +109,204 @@ func init() {
 	EDINETBucketName = os.Getenv("EDINET_BUCKET_NAME")
 	RegisterSingleReport = os.Getenv("REGISTER_SINGLE_REPORT")
 	Parallel = os.Getenv("PARALLEL")
+	// 1 資料あたりのメモリ使用量の上限 (MB)
+	maxDocumentMB, err := strconv.Atoi(os.Getenv("MAX_DOCUMENT_MB"))
+	if err == nil && maxDocumentMB > 0 {
+		MaxDocumentBytes = int64(maxDocumentMB) * 1024 * 1024
+	}
+	// サマリーの取得元 (csv の場合は EDINET の CSV の値を使う)
+	ExtractionSource = os.Getenv("EXTRACTION_SOURCE")
+	// 勘定科目の辞書に使うタクソノミの名称リンクベースのディレクトリ
+	TaxonomyLabelDir = os.Getenv("TAXONOMY_LABEL_DIR")
+	// 投資信託の有価証券報告書も登録する場合は true
+	FundMode = os.Getenv("FUND_MODE")
+	DryRun = os.Getenv("DRY_RUN")
+	DryRunDir = os.Getenv("DRY_RUN_DIR")
+	if DryRunDir == "" {
+		DryRunDir = "dry-run"
+	}
 
 	// /tmp ディレクトリに invalid-summary.json と failed.json を登録する
 	CreateFailedFiles()
 }
 
-func Unzip(source, destination string) (string, error) {
-	// ZIPファイルをオープン
-	r, err := zip.OpenReader(source)
+/*
+EDINET 書類取得 API から書類をダウンロードし、メモリ上に読み込む
+@params
+
+	docType: 1 (XBRL), 2 (PDF), 3 (代替書面・添付文書), 4 (英文ファイル), 5 (CSV)
+*/
+func DownloadDocument(client *http.Client, docID string, docType int) ([]byte, error) {
+	url := fmt.Sprintf("https://api.edinet-fsa.go.jp/api/v2/documents/%s?type=%d&Subscription-Key=%s", docID, docType, EDINETSubAPIKey)
+	resp, err := client.Get(url)
 	if err != nil {
-		return "", fmt.Errorf("failed to open zip file: %v", err)
+		return nil, err
 	}
-	defer r.Close()
+	defer resp.Body.Close()
 
-	var XBRLFilepath string
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("書類取得 API のステータスコードが不正です: %d", resp.StatusCode)
+	}
+	return ReadWithLimit(resp.Body, MaxDocumentBytes)
+}
 
-	// ZIP内の各ファイルを処理
-	for _, f := range r.File {
-		extension := filepath.Ext(f.Name)
-		underPublic := strings.Contains(f.Name, "PublicDoc")
+/*
+上限サイズまで読み込む
+上限を超えた場合はエラーを返す
+*/
+func ReadWithLimit(r io.Reader, limit int64) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("サイズが上限 (%d bytes) を超えています", limit)
+	}
+	return body, nil
+}
 
-		// ファイル名に EDINETコードが含まれる かつ 拡張子が .xbrl の場合のみ処理する
-		if underPublic && extension == ".xbrl" {
-			// ファイル名を作成
-			fpath := filepath.Join(destination, f.Name)
+/*
+メモリ上の ZIP ファイルから XBRL インスタンスをすべて取得し、種類ごとに分類する
+本体の有価証券報告書は PublicDoc 配下の jpcrp (投資信託の場合は jpsps) 名前空間のインスタンスから選ぶ
+*/
+func Unzip(data []byte) (*XBRLArchive, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip file: %v", err)
+	}
 
-			// ディレクトリの場合は作成
-			if f.FileInfo().IsDir() {
-				os.MkdirAll(fpath, os.ModePerm)
-				continue
-			}
+	var archive XBRLArchive
+	var mainCandidates []XBRLInstance
 
-			// ファイルの場合はファイルを作成し、内容をコピー
-			if err := os.MkdirAll(filepath.Dir(fpath), os.ModePerm); err != nil {
-				return "", err
-			}
+	// ZIP内の各ファイルを処理
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		// 拡張子が .xbrl のファイルと PublicDoc のインライン XBRL・名称リンクベースのみ処理する
+		isManuscript := strings.Contains(f.Name, "PublicDoc") && strings.HasSuffix(f.Name, "_ixbrl.htm")
+		isLabelFile := strings.Contains(f.Name, "PublicDoc") && (strings.HasSuffix(f.Name, "_lab.xml") || strings.HasSuffix(f.Name, "_lab-en.xml"))
+		if filepath.Ext(f.Name) != ".xbrl" && !isManuscript && !isLabelFile {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		body, err := ReadWithLimit(rc, MaxDocumentBytes)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
 
-			outFile, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
-			if err != nil {
-				return "", err
-			}
+		if isManuscript {
+			archive.Manuscripts = append(archive.Manuscripts, IXBRLDocument{
+				Path: f.Name,
+				Body: body,
+			})
+			continue
+		}
+		if isLabelFile {
+			archive.LabelFiles = append(archive.LabelFiles, ArchiveFile{
+				Path: f.Name,
+				Body: body,
+			})
+			continue
+		}
 
-			rc, err := f.Open()
-			if err != nil {
-				return "", err
-			}
+		instance := XBRLInstance{
+			Path: f.Name,
+			Kind: ClassifyXBRLInstance(f.Name, body),
+			Body: body,
+		}
+		switch instance.Kind {
+		case XBRLKindMain:
+			mainCandidates = append(mainCandidates, instance)
+		case XBRLKindAudit:
+			archive.Audits = append(archive.Audits, instance)
+		default:
+			archive.Attachments = append(archive.Attachments, instance)
+		}
+	}
+
+	if len(mainCandidates) == 0 {
+		return nil, errors.New("ZIP 内に PublicDoc の XBRL ファイルがありません")
+	}
+	// 本体が複数ある場合は有価証券報告書 (asr) を優先し、残りは添付として扱う
+	mainIndex := 0
+	for i, candidate := range mainCandidates {
+		if strings.Contains(filepath.Base(candidate.Path), "-asr-") {
+			mainIndex = i
+			break
+		}
+	}
+	for i, candidate := range mainCandidates {
+		if i == mainIndex {
+			archive.Main = candidate
+		} else {
+			candidate.Kind = XBRLKindAttachment
+			archive.Attachments = append(archive.Attachments, candidate)
+		}
+	}
+	return &archive, nil
+}
 
-			_, err = io.Copy(outFile, rc)
+/*
+XBRL インスタンスの種類を判定する
+@returns
 
-			// リソースを閉じる
-			outFile.Close()
-			rc.Close()
+	main:       PublicDoc 配下の jpcrp もしくは jpsps (内国投資信託などの特定有価証券) 名前空間のインスタンス (有価証券報告書本体)
+	audit:      AuditDoc 配下もしくは jpaud 名前空間のインスタンス (監査報告書)
+	attachment: それ以外
+*/
+func ClassifyXBRLInstance(path string, body []byte) string {
+	namespaces := XBRLNamespaces(body)
+	if strings.Contains(path, "AuditDoc") || namespaces["jpaud"] {
+		return XBRLKindAudit
+	}
+	if strings.Contains(path, "PublicDoc") && (namespaces["jpcrp"] || namespaces["jpsps"]) {
+		return XBRLKindMain
+	}
+	return XBRLKindAttachment
+}
 
-			if err != nil {
-				return "", err
+/*
+ルート要素で宣言されている EDINET タクソノミの名前空間 (jpcrp, jppfs, jpigp, jpaud など) を返す
+*/
+func XBRLNamespaces(body []byte) map[string]bool {
+	namespaces := make(map[string]bool)
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return namespaces
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		for _, attr := range start.Attr {
+			if attr.Name.Space != "xmlns" {
+				continue
+			}
+			// 例: http://disclosure.edinet-fsa.go.jp/taxonomy/jpcrp/2023-12-01/jpcrp_cor
+			for _, part := range strings.Split(attr.Value, "/") {
+				if strings.HasPrefix(part, "jp") && !strings.Contains(part, "_") {
+					namespaces[part] = true
+				}
+			}
+			// 提出者別タクソノミ 例: http://disclosure.edinet-fsa.go.jp/jpcrp030000/asr/001/E00001-000/...
+			if strings.Contains(attr.Value, "/jpcrp") {
+				namespaces["jpcrp"] = true
+			}
+			// 投資信託の提出者別タクソノミ 例: http://disclosure.edinet-fsa.go.jp/jpsps070000/asr/001/G00001-000/...
+			if strings.Contains(attr.Value, "/jpsps") {
+				namespaces["jpsps"] = true
 			}
-
-			XBRLFilepath = f.Name
 		}
+		// ルート要素のみ確認する
+		return namespaces
 	}
-	// zipファイルを削除
-	err = os.RemoveAll(source)
-	if err != nil {
-		fmt.Println("zip ファイル削除エラー: ", err)
-	}
-	return XBRLFilepath, nil
 }
 
 /*
@@ -240,6 +378,11 @@ func GetReports() ([]Result, error) {
 				s.DateKey = dateKey
 				results = append(results, s)
 			}
+			// 有価証券報告書（内国投資信託受益証券）
+			if FundMode == "true" && IsFundReport(s) {
+				s.DateKey = dateKey
+				results = append(results, s)
+			}
 		}
 
 		date = date.AddDate(0, 0, 1)
@@ -247,7 +390,14 @@ func GetReports() ([]Result, error) {
 	return results, nil
 }
 
-func RegisterReport(dynamoClient *dynamodb.Client, EDINETCode string, docID string, dateKey string, companyName string, periodStart string, periodEnd string, fundamental *Fundamental, wg *sync.WaitGroup) {
+/*
+書類一覧 API の結果 1 件分のレポートを登録する
+*/
+func RegisterReport(dynamoClient *dynamodb.Client, report Result, periodStart string, periodEnd string, fundamental *Fundamental, wg *sync.WaitGroup) {
+	EDINETCode := report.EdinetCode
+	docID := report.DocId
+	dateKey := report.DateKey
+	companyName := report.FilerName
 	fmt.Printf("===== ⭐️「%s」⭐️ =====\n", companyName)
 	// 並列で処理する場合
 	if Parallel == "true" {
@@ -284,6 +434,10 @@ func RegisterReport(dynamoClient *dynamodb.Client, EDINETCode string, docID stri
 	// XBRLファイルの中身
 	var body []byte
 	var parentPath string
+	// インライン XBRL (ZIP から取得した場合のみ)
+	var manuscripts []IXBRLDocument
+	// 名称リンクベース (ZIP から取得した場合のみ)
+	var labelFiles []ArchiveFile
 	if isDocRegistered {
 		getXBRLFromS3 := os.Getenv("GET_XBRL_FROM_S3")
 		var xbrlFileName string
@@ -296,13 +450,16 @@ func RegisterReport(dynamoClient *dynamodb.Client, EDINETCode string, docID stri
 				dateDocIDKey := fmt.Sprintf("%s/%s", dateKey, docID)
 
 				listOutput := ListS3Objects(S3Client, EDINETBucketName, dateDocIDKey)
-				if len(listOutput.Contents) > 0 {
-					firstFile := listOutput.Contents[0]
-					// S3 に登録済みのxbrlファイル
-					splitBySlash := strings.Split(*firstFile.Key, "/")
-					if len(splitBySlash) >= 3 {
-						xbrlFileName = splitBySlash[len(splitBySlash)-1]
+				for _, content := range listOutput.Contents {
+					// PDF や添付書類 (attach/, english/ 配下) は対象外
+					ext := filepath.Ext(*content.Key)
+					splitBySlash := strings.Split(*content.Key, "/")
+					if (ext != ".xbrl" && ext != ".html") || len(splitBySlash) != 3 {
+						continue
 					}
+					// S3 に登録済みのxbrlファイル
+					xbrlFileName = splitBySlash[len(splitBySlash)-1]
+					break
 				}
 			}
 			key := fmt.Sprintf("%s/%s/%s", dateKey, docID, xbrlFileName)
@@ -310,10 +467,10 @@ func RegisterReport(dynamoClient *dynamodb.Client, EDINETCode string, docID stri
 			output, _ := GetS3Object(S3Client, EDINETBucketName, key)
 			// HTML ファイルを取得し、HTML ファイルもなければ return
 			if output != nil {
-				readBody, err := io.ReadAll(output.Body)
+				readBody, err := ReadWithLimit(output.Body, MaxDocumentBytes)
 				if err != nil {
 					fmt.Println("io.ReadAll エラー: ", err)
-          return
+					return
 				}
 				body = readBody
 				defer output.Body.Close()
@@ -329,10 +486,10 @@ func RegisterReport(dynamoClient *dynamodb.Client, EDINETCode string, docID stri
 					fmt.Println("元データの XBRL, HTML ファイルがないため処理を終了します❗️")
 					return
 				}
-				HTMLReadBody, err := io.ReadAll(HTMLOutput.Body)
+				HTMLReadBody, err := ReadWithLimit(HTMLOutput.Body, MaxDocumentBytes)
 				if err != nil {
 					fmt.Println("io.ReadAll エラー: ", err)
-          return
+					return
 				}
 				body = HTMLReadBody
 				defer HTMLOutput.Body.Close()
@@ -341,70 +498,56 @@ func RegisterReport(dynamoClient *dynamodb.Client, EDINETCode string, docID stri
 	} else {
 		fmt.Printf("「%s」のレポート (%s) を API から取得します🎾\n", companyName, docID)
 		ApiTimes += 1
-		url := fmt.Sprintf("https://api.edinet-fsa.go.jp/api/v2/documents/%s?type=1&Subscription-Key=%s", docID, EDINETSubAPIKey)
-		resp, err := client.Get(url)
+		// ZIPファイルはメモリ上に読み込む
+		zipBody, err := DownloadDocument(client, docID, 1)
 		if err != nil {
 			ErrMsg = "http get error : "
 			RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
 			return
 		}
-		defer resp.Body.Close()
-
-		// Lambda 用に /tmp を足す
-		dirPath := filepath.Join("/tmp", "XBRL")
-		fmt.Println("XBRL DirPath ⭐️: ", dirPath)
-		zipFileName := fmt.Sprintf("%s.zip", docID)
-		path := filepath.Join(dirPath, zipFileName)
-
-		// ディレクトリが存在しない場合は作成
-		err = os.MkdirAll(dirPath, 0777)
-		if err != nil {
-			ErrMsg = "Error creating XBRL directory: "
-			RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
-			return
-		}
-		file, err := os.Create(path)
-		if err != nil {
-			ErrMsg = "Error while creating the file: "
-			RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
-			return
-		}
-		defer file.Close()
-
-		// レスポンスのBody（ZIPファイルの内容）をファイルに書き込む
-		_, err = io.Copy(file, resp.Body)
-		if err != nil {
-			ErrMsg = "Error while saving the file: "
-			RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
-			return
-		}
 
 		// ZIPファイルを解凍
-		unzipDst := filepath.Join(dirPath, docID)
-		XBRLFilepath, err := Unzip(path, unzipDst)
+		archive, err := Unzip(zipBody)
 		if err != nil {
 			ErrMsg = "Error unzipping file: "
 			RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
 			return
 		}
-
-		// XBRLファイルの取得
-		// Lambda 用に /tmp を足す
-		parentPath = filepath.Join("/tmp", "XBRL", docID, XBRLFilepath)
-		XBRLFile, err := os.Open(parentPath)
-		if err != nil {
-			ErrMsg = "XBRL open err: "
-			RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
-			return
-		}
-		// ここに xbrl ファイルがあるのでは❓
-		body, err = io.ReadAll(XBRLFile)
-		if err != nil {
-			ErrMsg = "XBRL read err: "
-			RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
-			return
+		parentPath = archive.Main.Path
+		body = archive.Main.Body
+		manuscripts = archive.Manuscripts
+		labelFiles = archive.LabelFiles
+		if len(archive.Attachments) > 0 {
+			fmt.Printf("「%s」のレポート (%s) には本体以外の XBRL インスタンスが %d 件あります\n", companyName, docID, len(archive.Attachments))
+		}
+
+		// 監査報告書
+		if len(archive.Audits) > 0 {
+			auditSummary := CreateAuditSummary(companyName, periodStart, periodEnd, archive.Audits)
+			auditFileNamePattern := fmt.Sprintf("%s-%s-Audit-from-%s-to-%s", EDINETCode, docID, periodStart, periodEnd)
+			auditJSONBody, err := CreateJSON(docID, dateKey, auditSummary)
+			if err == nil {
+				var auditWg sync.WaitGroup
+				if Parallel == "true" {
+					auditWg.Add(1)
+				}
+				PutFileToS3(docID, dateKey, EDINETCode, companyName, auditFileNamePattern, "json", auditJSONBody, objectKeys, &auditWg)
+			}
 		}
 	}
+	// PDF
+	if report.PdfFlag == "1" {
+		RegisterPDF(dynamoClient, client, EDINETCode, docID, dateKey, companyName)
+	}
+	// 代替書面・添付文書
+	if report.AttachDocFlag == "1" {
+		RegisterAttachments(client, docID, dateKey, companyName)
+	}
+	// 英文ファイル
+	if report.EnglishDocFlag == "1" {
+		RegisterEnglishDocuments(client, EDINETCode, docID, dateKey, companyName, periodStart, periodEnd, objectKeys)
+	}
+
 	// xbrlKey = 20060102/{DocID}/~~~~.xbrl
 	splitBySlash := strings.Split(parentPath, "/")
 	xbrlFile := splitBySlash[len(splitBySlash)-1]
@@ -424,98 +567,122 @@ func RegisterReport(dynamoClient *dynamodb.Client, EDINETCode string, docID stri
 		return
 	}
 
-	// 【連結貸借対照表】
-	consolidatedBSPattern := `(?s)<jpcrp_cor:ConsolidatedBalanceSheetTextBlock contextRef="CurrentYearDuration">(.*?)</jpcrp_cor:ConsolidatedBalanceSheetTextBlock>`
-	consolidatedBSRe := regexp.MustCompile(consolidatedBSPattern)
-	// fmt.Println("元データ: ", string(body))
-	consolidatedBSMatches := consolidatedBSRe.FindString(string(body))
+	// インライン XBRL に財務諸表がそろっていればそちらを使う
+	matches := ResolveStatementMatches(body, manuscripts)
+	if matches.FromIXBRL {
+		fmt.Printf("「%s」のレポート (%s) はインライン XBRL の財務諸表を使用します\n", companyName, docID)
+		CompareIXBRLFacts(docID, companyName, body, manuscripts)
+	}
 
-	// 【連結貸借対照表（IFRS）】※ 【連結財政状態計算書】が正式名称
-	// consolidatedBSIFRSPattern := `(?s)<jpigp_cor:f contextRef="CurrentYearDuration">(.*?)</jpigp_cor:f>`
-	consolidatedBSIFRSPattern := `(?s) <jpigp_cor:ConsolidatedStatementOfFinancialPositionIFRSTextBlock contextRef="CurrentYearDuration">(.*?)</jpigp_cor:ConsolidatedStatementOfFinancialPositionIFRSTextBlock>`
-	consolidatedBSIFRSRe := regexp.MustCompile(consolidatedBSIFRSPattern)
-	consolidatedBSIFRSMatches := consolidatedBSIFRSRe.FindString(string(body))
+	// 【証券コード】
+	securityCodePattern := `<jpdei_cor:SecurityCodeDEI[^>]*>(\d+)<\/jpdei_cor:SecurityCodeDEI>`
+	// securityCodePattern := `(?s)<jpdei_cor:SecurityCodeDEI contextRef="FilingDateInstant">(.*?)</jpdei_cor:SecurityCodeDEI>`
+	securityCodeRe := regexp.MustCompile(securityCodePattern)
+	// securityCodeMatches := securityCodeRe.FindString(string(body))
+	securityCodeMatches := securityCodeRe.FindStringSubmatch(string(body))
+	var securityCode string
+	if len(securityCodeMatches) > 1 {
+		// fmt.Printf("「%s」の証券コード: %s\n", companyName, securityCodeMatches[1])
+		securityCode = securityCodeMatches[1]
+	}
 
-	// 【貸借対照表】
-	soloBSPattern := `(?s)<jpcrp_cor:BalanceSheetTextBlock contextRef="CurrentYearDuration">(.*?)</jpcrp_cor:BalanceSheetTextBlock>`
-	soloBSRe := regexp.MustCompile(soloBSPattern)
-	soloBSMatches := soloBSRe.FindString(string(body))
+	// 証券コード登録
+	err = UpdateSecCode(dynamoClient, EDINETCode, securityCode)
+	if err != nil {
+		fmt.Println("UpdateSecCode error: ", err)
+	}
 
-	// 【連結損益計算書】
-	consolidatedPLPattern := `(?s)<jpcrp_cor:ConsolidatedStatementOfIncomeTextBlock contextRef="CurrentYearDuration">(.*?)</jpcrp_cor:ConsolidatedStatementOfIncomeTextBlock>`
-	consolidatedPLRe := regexp.MustCompile(consolidatedPLPattern)
-	consolidatedPLMatches := consolidatedPLRe.FindString(string(body))
+	// XBRL インスタンスの事実 (DEI, 主要な経営指標等の推移など)
+	facts, err := ParseInstanceFacts(body)
+	if err != nil {
+		fmt.Println("ParseInstanceFacts error: ", err)
+	}
 
-	// 【連結損益計算書（IFRS）】
-	consolidatedPLIFRSPattern := `(?s)<jpigp_cor:ConsolidatedStatementOfProfitOrLossIFRSTextBlock contextRef="CurrentYearDuration">(.*?)</jpigp_cor:ConsolidatedStatementOfProfitOrLossIFRSTextBlock>`
-	condolidatedPLIFRSRe := regexp.MustCompile(consolidatedPLIFRSPattern)
-	consolidatedPLIFRSMatches := condolidatedPLIFRSRe.FindString(string(body))
+	// 企業の概況の登録
+	overview := CreateCompanyOverview(facts)
+	err = UpdateCompanyOverview(dynamoClient, EDINETCode, overview)
+	if err != nil {
+		fmt.Println("UpdateCompanyOverview error: ", err)
+	}
 
-	// 【損益計算書】
-	soloPLPattern := `(?s)<jpcrp_cor:StatementOfIncomeTextBlock contextRef="CurrentYearDuration">(.*?)</jpcrp_cor:StatementOfIncomeTextBlock>`
-	soloPLRe := regexp.MustCompile(soloPLPattern)
-	soloPLMatches := soloPLRe.FindString(string(body))
+	// 業種別テンプレート (銀行・保険・証券は売上高・売上原価がないため専用の勘定科目で検証する)
+	industryTemplate := SelectIndustryTemplate(overview.IndustryCode, facts)
+	if industryTemplate.Industry != IndustryGeneral {
+		fmt.Printf("「%s」は%sのテンプレートでサマリーを作成します🏦\n", companyName, industryTemplate.Label)
+	}
 
-	// 【連結キャッシュ・フロー計算書】
-	consolidatedCFPattern := `(?s)<jpcrp_cor:ConsolidatedStatementOfCashFlowsTextBlock contextRef="CurrentYearDuration">(.*?)</jpcrp_cor:ConsolidatedStatementOfCashFlowsTextBlock>`
-	consolidatedCFRe := regexp.MustCompile(consolidatedCFPattern)
-	consolidatedCFMattches := consolidatedCFRe.FindString(string(body))
-	// 【連結キャッシュ・フロー計算書 (IFRS)】
-	consolidatedCFIFRSPattern := `(?s)<jpigp_cor:ConsolidatedStatementOfCashFlowsIFRSTextBlock contextRef="CurrentYearDuration">(.*?)</jpigp_cor:ConsolidatedStatementOfCashFlowsIFRSTextBlock>`
-	consolidatedCFIFRSRe := regexp.MustCompile(consolidatedCFIFRSPattern)
-	consolidatedCFIFRSMattches := consolidatedCFIFRSRe.FindString(string(body))
+	// 勘定科目の辞書 (書類の名称リンクベースのラベルを加える)
+	labelDictionary := FilingLabelDictionary(labelFiles)
 
-	// 【キャッシュ・フロー計算書】
-	soloCFPattern := `(?s)<jpcrp_cor:StatementOfCashFlowsTextBlock contextRef="CurrentYearDuration">(.*?)</jpcrp_cor:StatementOfCashFlowsTextBlock>`
-	soloCFRe := regexp.MustCompile(soloCFPattern)
-	soloCFMattches := soloCFRe.FindString(string(body))
+	// セグメント情報
+	segmentSummary, hasSegments := CreateSegmentSummary(companyName, periodStart, periodEnd, xbrl.Contexts, facts, CreateLabelMap(labelFiles, "ja"))
+	if hasSegments {
+		segmentJSONBody, err := CreateJSON(docID, dateKey, segmentSummary)
+		if err == nil {
+			var segmentWg sync.WaitGroup
+			if Parallel == "true" {
+				segmentWg.Add(1)
+			}
+			segmentFileNamePattern := fmt.Sprintf("%s-%s-Segments-from-%s-to-%s", EDINETCode, docID, periodStart, periodEnd)
+			PutFileToS3(docID, dateKey, EDINETCode, companyName, segmentFileNamePattern, "json", segmentJSONBody, objectKeys, &segmentWg)
+		}
+	}
 
-	// 【キャッシュ・フロー計算書 (IFRS)】
-	soloCFIFRSPattern := `(?s)<jpcrp_cor:StatementOfCashFlowsIFRSTextBlock contextRef="CurrentYearDuration">(.*?)</jpcrp_cor:StatementOfCashFlowsIFRSTextBlock>`
-	soloCFIFRSRe := regexp.MustCompile(soloCFIFRSPattern)
-	soloCFIFRSMattches := soloCFIFRSRe.FindString(string(body))
-
-  // 【証券コード】
-  securityCodePattern := `<jpdei_cor:SecurityCodeDEI[^>]*>(\d+)<\/jpdei_cor:SecurityCodeDEI>`
-  // securityCodePattern := `(?s)<jpdei_cor:SecurityCodeDEI contextRef="FilingDateInstant">(.*?)</jpdei_cor:SecurityCodeDEI>`
-  securityCodeRe := regexp.MustCompile(securityCodePattern)
-  // securityCodeMatches := securityCodeRe.FindString(string(body))
-  securityCodeMatches := securityCodeRe.FindStringSubmatch(string(body))
-  var securityCode string
-  if len(securityCodeMatches) > 1 {
-    // fmt.Printf("「%s」の証券コード: %s\n", companyName, securityCodeMatches[1])
-    securityCode = securityCodeMatches[1]
-  }
-
-  // 証券コード登録
-  err = UpdateSecCode(dynamoClient, EDINETCode, securityCode)
-  if err != nil {
-    fmt.Println("UpdateSecCode error: ", err)
-  }
+	// 大株主・株式・配当の情報
+	shareholderSummary, hasShareholders := CreateShareholderSummary(companyName, periodStart, periodEnd, facts)
+	if hasShareholders {
+		var shareholderWg sync.WaitGroup
+		if Parallel == "true" {
+			shareholderWg.Add(1)
+		}
+		shareholderFileNamePattern := fmt.Sprintf("%s-%s-Shareholders-from-%s-to-%s", EDINETCode, docID, periodStart, periodEnd)
+		HandleRegisterJSON(docID, dateKey, EDINETCode, companyName, shareholderFileNamePattern, shareholderSummary, objectKeys, &shareholderWg)
+	}
+
+	// 役員・コーポレート・ガバナンスの情報
+	governanceSummary, hasGovernance := CreateGovernanceSummary(companyName, periodStart, periodEnd, xbrl.Contexts, facts)
+	if hasGovernance {
+		var governanceWg sync.WaitGroup
+		if Parallel == "true" {
+			governanceWg.Add(1)
+		}
+		governanceFileNamePattern := fmt.Sprintf("%s-%s-Governance-from-%s-to-%s", EDINETCode, docID, periodStart, periodEnd)
+		HandleRegisterJSON(docID, dateKey, EDINETCode, companyName, governanceFileNamePattern, governanceSummary, objectKeys, &governanceWg)
+	}
 
 	// 貸借対照表HTMLをローカルに作成
-	doc, err := CreateHTML(docID, dateKey, "BS", consolidatedBSMatches, consolidatedBSIFRSMatches, soloBSMatches, consolidatedPLMatches, consolidatedPLIFRSMatches, soloPLMatches, BSFileNamePattern, PLFileNamePattern)
+	doc, BSHTMLBody, err := CreateHTML(docID, dateKey, "BS", matches)
 	if err != nil {
 		ErrMsg = "PL CreateHTML エラー: "
 		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
 		return
 	}
 	// 損益計算書HTMLをローカルに作成
-	plDoc, err := CreateHTML(docID, dateKey, "PL", consolidatedBSMatches, consolidatedBSIFRSMatches, soloBSMatches, consolidatedPLMatches, consolidatedPLIFRSMatches, soloPLMatches, BSFileNamePattern, PLFileNamePattern)
+	plDoc, PLHTMLBody, err := CreateHTML(docID, dateKey, "PL", matches)
 	if err != nil {
 		ErrMsg = "PL CreateHTML エラー: "
 		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
 		return
 	}
 
+	// 連結・個別の範囲
+	bsLabel, _ := SelectStatementMatch("BS", matches)
+	plLabel, plMatch := SelectStatementMatch("PL", matches)
+	cfLabel, _ := SelectStatementMatch("CF", matches)
+	bsScope := StatementScope(bsLabel)
+	plScope := StatementScope(plLabel)
+	cfScope := StatementScope(cfLabel)
+
 	// 貸借対照表データ
 	var summary Summary
 	summary.CompanyName = companyName
 	summary.PeriodStart = periodStart
 	summary.PeriodEnd = periodEnd
+	summary.Scope = bsScope
 	// UpdateEverySumary に置き換える
 	// UpdateSummary(doc, docID, dateKey, &summary, fundamental)
-	UpdateEverySummary(doc, docID, dateKey, "bs", &summary, nil, nil, fundamental)
+	UpdateEverySummary(doc, docID, dateKey, "bs", &summary, nil, nil, fundamental, labelDictionary, nil)
+	UpdateIndustryBSSummary(doc, industryTemplate, &summary, fundamental)
 	// fmt.Println("BSSummary ⭐️: ", summary)
 
 	// BS バリデーション用
@@ -526,16 +693,24 @@ func RegisterReport(dynamoClient *dynamodb.Client, EDINETCode string, docID stri
 	plSummary.CompanyName = companyName
 	plSummary.PeriodStart = periodStart
 	plSummary.PeriodEnd = periodEnd
+	plSummary.Scope = plScope
 	// UpdateEverySummary で置き換える
 	// UpdatePLSummary(plDoc, docID, dateKey, &plSummary, fundamental)
-	UpdateEverySummary(plDoc, docID, dateKey, "pl", nil, &plSummary, nil, fundamental)
+	UpdateEverySummary(plDoc, docID, dateKey, "pl", nil, &plSummary, nil, fundamental, labelDictionary, nil)
+	UpdateIndustryPLSummary(plDoc, industryTemplate, &plSummary, fundamental)
+
+	// 1株当たり当期純利益 (主要な経営指標等の推移から取得)
+	UpdateEPS(facts, plScope == ScopeConsolidated, &plSummary, fundamental)
 
-	isPLSummaryValid := ValidatePLSummary(plSummary)
+	// 包括利益計算書 (損益計算書と別の場合)
+	RegisterCIStatement(docID, dateKey, EDINETCode, companyName, periodStart, periodEnd, matches, plMatch, &plSummary, fundamental, labelDictionary, objectKeys)
+
+	isPLSummaryValid := ValidateIndustryPLSummary(plSummary, industryTemplate)
 	// fmt.Println("PLSummary ⭐️: ", plSummary)
 
 	// CF計算書データ
 	cfFileNamePattern := fmt.Sprintf("%s-%s-CF-from-%s-to-%s", EDINETCode, docID, periodStart, periodEnd)
-	cfHTML, err := CreateCFHTML(docID, dateKey, cfFileNamePattern, string(body), consolidatedCFMattches, consolidatedCFIFRSMattches, soloCFMattches, soloCFIFRSMattches)
+	cfHTML, cfHTMLBody, err := CreateCFHTML(docID, dateKey, matches)
 	if err != nil {
 		ErrMsg = "CreateCFHTML err: "
 		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
@@ -545,9 +720,21 @@ func RegisterReport(dynamoClient *dynamodb.Client, EDINETCode string, docID stri
 	cfSummary.CompanyName = companyName
 	cfSummary.PeriodStart = periodStart
 	cfSummary.PeriodEnd = periodEnd
+	cfSummary.Scope = cfScope
 	// UpdateEverySummary に置き換える
 	// UpdateCFSummary(docID, dateKey, cfHTML, &cfSummary)
-	UpdateEverySummary(cfHTML, docID, dateKey, "cf", nil, nil, &cfSummary, nil)
+	UpdateEverySummary(cfHTML, docID, dateKey, "cf", nil, nil, &cfSummary, nil, labelDictionary, nil)
+
+	// CSV の値でサマリーを上書きする場合
+	if ExtractionSource == "csv" {
+		ApplyCSVSource(client, docID, dateKey, EDINETCode, companyName, periodStart, periodEnd, matches, &summary, &plSummary, &cfSummary, fundamental, objectKeys)
+		isPLSummaryValid = ValidateIndustryPLSummary(plSummary, industryTemplate)
+	}
+	// フリーCF の計算と期首・期末残高の整合性チェック
+	CompleteCFSummary(&cfSummary)
+	if !cfSummary.IsCashReconciled {
+		fmt.Printf("「%s」の CF 計算書の期首残高 + 増減額が期末残高と一致しません❗️ (期首: %d, 増減額: %d, 期末: %d)\n", companyName, cfSummary.StartCash.Current, cfSummary.NetChange.Current, cfSummary.EndCash.Current)
+	}
 	isCFSummaryValid := ValidateCFSummary(cfSummary)
 
 	// CF計算書バリデーション後
@@ -563,20 +750,20 @@ func RegisterReport(dynamoClient *dynamodb.Client, EDINETCode string, docID stri
 
 	if Parallel == "true" {
 		// 並列で処理する場合
-		go PutFileToS3(docID, dateKey, EDINETCode, companyName, cfFileNamePattern, "html", objectKeys, &putFileWg)
+		go PutScopedFileToS3(docID, dateKey, EDINETCode, companyName, cfScope, cfFileNamePattern, "html", cfHTMLBody, objectKeys, &putFileWg)
 	} else {
 		// 直列で処理する場合
-		PutFileToS3(docID, dateKey, EDINETCode, companyName, cfFileNamePattern, "html", objectKeys, &putFileWg)
+		PutScopedFileToS3(docID, dateKey, EDINETCode, companyName, cfScope, cfFileNamePattern, "html", cfHTMLBody, objectKeys, &putFileWg)
 	}
 
 	if isCFSummaryValid {
 		// S3 に JSON 送信
 		if Parallel == "true" {
 			// 並列で処理する場合
-			go HandleRegisterJSON(docID, dateKey, EDINETCode, companyName, cfFileNamePattern, cfSummary, objectKeys, &putFileWg)
+			go HandleRegisterScopedJSON(docID, dateKey, EDINETCode, companyName, cfScope, cfFileNamePattern, cfSummary, objectKeys, &putFileWg)
 		} else {
 			// 直列で処理する場合
-			HandleRegisterJSON(docID, dateKey, EDINETCode, companyName, cfFileNamePattern, cfSummary, objectKeys, &putFileWg)
+			HandleRegisterScopedJSON(docID, dateKey, EDINETCode, companyName, cfScope, cfFileNamePattern, cfSummary, objectKeys, &putFileWg)
 		}
 
 		// TODO: invalid-summary.json から削除
@@ -600,8 +787,11 @@ func RegisterReport(dynamoClient *dynamodb.Client, EDINETCode string, docID stri
 		putFileWg.Wait()
 	}
 
+	// 自己資本比率・D/E レシオなど
+	UpdateBSRatios(&summary, fundamental)
+
 	// 貸借対照表バリデーションなしバージョン
-	_, err = CreateJSON(docID, dateKey, BSFileNamePattern, summary)
+	BSJSONBody, err := CreateJSON(docID, dateKey, summary)
 	if err != nil {
 		ErrMsg = "BS JSON ファイル作成エラー: "
 		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
@@ -617,19 +807,19 @@ func RegisterReport(dynamoClient *dynamodb.Client, EDINETCode string, docID stri
 	// BS JSON 送信
 	if Parallel == "true" {
 		// 並列で処理する場合
-		go PutFileToS3(docID, dateKey, EDINETCode, companyName, BSFileNamePattern, "json", objectKeys, &putBsWg)
+		go PutScopedFileToS3(docID, dateKey, EDINETCode, companyName, bsScope, BSFileNamePattern, "json", BSJSONBody, objectKeys, &putBsWg)
 	} else {
 		// 直列で処理する場合
-		PutFileToS3(docID, dateKey, EDINETCode, companyName, BSFileNamePattern, "json", objectKeys, &putBsWg)
+		PutScopedFileToS3(docID, dateKey, EDINETCode, companyName, bsScope, BSFileNamePattern, "json", BSJSONBody, objectKeys, &putBsWg)
 	}
 
 	// BS HTML 送信
 	if Parallel == "true" {
 		// 並列で処理する場合
-		go PutFileToS3(docID, dateKey, EDINETCode, companyName, BSFileNamePattern, "html", objectKeys, &putBsWg)
+		go PutScopedFileToS3(docID, dateKey, EDINETCode, companyName, bsScope, BSFileNamePattern, "html", BSHTMLBody, objectKeys, &putBsWg)
 	} else {
 		// 直列で処理する場合
-		PutFileToS3(docID, dateKey, EDINETCode, companyName, BSFileNamePattern, "html", objectKeys, &putBsWg)
+		PutScopedFileToS3(docID, dateKey, EDINETCode, companyName, bsScope, BSFileNamePattern, "html", BSHTMLBody, objectKeys, &putBsWg)
 	}
 
 	// 並列で処理する場合
@@ -647,14 +837,14 @@ func RegisterReport(dynamoClient *dynamodb.Client, EDINETCode string, docID stri
 	// PL HTML 送信 (バリデーション結果に関わらず)
 	if Parallel == "true" {
 		// 並列で処理する場合
-		go PutFileToS3(docID, dateKey, EDINETCode, companyName, PLFileNamePattern, "html", objectKeys, &putPlWg)
+		go PutScopedFileToS3(docID, dateKey, EDINETCode, companyName, plScope, PLFileNamePattern, "html", PLHTMLBody, objectKeys, &putPlWg)
 	} else {
 		// 直列で処理する場合
-		PutFileToS3(docID, dateKey, EDINETCode, companyName, PLFileNamePattern, "html", objectKeys, &putPlWg)
+		PutScopedFileToS3(docID, dateKey, EDINETCode, companyName, plScope, PLFileNamePattern, "html", PLHTMLBody, objectKeys, &putPlWg)
 	}
 
 	if isPLSummaryValid {
-		_, err = CreateJSON(docID, dateKey, PLFileNamePattern, plSummary)
+		PLJSONBody, err := CreateJSON(docID, dateKey, plSummary)
 		if err != nil {
 			ErrMsg = "PL JSON ファイル作成エラー: "
 			RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
@@ -663,10 +853,10 @@ func RegisterReport(dynamoClient *dynamodb.Client, EDINETCode string, docID stri
 		// PL JSON 送信
 		if Parallel == "true" {
 			// 並列で処理する場合
-			go PutFileToS3(docID, dateKey, EDINETCode, companyName, PLFileNamePattern, "json", objectKeys, &putPlWg)
+			go PutScopedFileToS3(docID, dateKey, EDINETCode, companyName, plScope, PLFileNamePattern, "json", PLJSONBody, objectKeys, &putPlWg)
 		} else {
 			// 直列で処理する場合
-			PutFileToS3(docID, dateKey, EDINETCode, companyName, PLFileNamePattern, "json", objectKeys, &putPlWg)
+			PutScopedFileToS3(docID, dateKey, EDINETCode, companyName, plScope, PLFileNamePattern, "json", PLJSONBody, objectKeys, &putPlWg)
 		}
 
 		// TODO: invalid-summary.json から削除
@@ -687,16 +877,27 @@ func RegisterReport(dynamoClient *dynamodb.Client, EDINETCode string, docID stri
 		putPlWg.Wait()
 	}
 
-	// XBRL ファイルの削除
-	xbrlDir := filepath.Join("XBRL", docID)
-	err = os.RemoveAll(xbrlDir)
-	if err != nil {
-		ErrMsg = "XBRL ディレクトリ削除エラー: "
-		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+	// 連結財務諸表を作成している場合は個別財務諸表も登録する
+	soloTimeSeriesSource := RegisterSoloStatements(docID, dateKey, EDINETCode, companyName, periodStart, periodEnd, matches, industryTemplate, labelDictionary, objectKeys)
+
+	// 株主資本等変動計算書
+	RegisterSSStatements(docID, dateKey, EDINETCode, companyName, periodStart, periodEnd, matches, objectKeys)
+
+	// 企業ごとの時系列データ
+	timeSeriesSources := []TimeSeriesSource{
+		{
+			Summary:          summary,
+			PLSummary:        plSummary,
+			CFSummary:        cfSummary,
+			IsPLSummaryValid: isPLSummaryValid,
+			IsCFSummaryValid: isCFSummaryValid,
+		},
+		soloTimeSeriesSource,
 	}
+	RegisterTimeSeries(docID, dateKey, EDINETCode, companyName, periodStart, periodEnd, timeSeriesSources)
 
 	// ファンダメンタル用jsonの送信
-	if ValidateFundamentals(*fundamental) {
+	if ValidateIndustryFundamentals(*fundamental, industryTemplate) {
 		RegisterFundamental(dynamoClient, docID, dateKey, *fundamental, EDINETCode)
 
 		// invalid-summary.json から削除
@@ -749,7 +950,15 @@ func ValidatePLSummary(plSummary PLSummary) bool {
 }
 
 func RegisterCompany(dynamoClient *dynamodb.Client, EDINETCode string, companyName string, isSummaryValid bool, isPLSummaryValid bool) {
-	foundItems, err := QueryByName(dynamoClient, TableName, companyName, EDINETCode)
+	var foundItems []map[string]types.AttributeValue
+	var err error
+	if DryRun == "true" {
+		// 同じ実行内で新規登録した企業を重複して登録しない
+		foundItems, err = QueryDryRunItems(TableName, "edinetCode", EDINETCode)
+	}
+	if err == nil && len(foundItems) == 0 {
+		foundItems, err = QueryByName(dynamoClient, TableName, companyName, EDINETCode)
+	}
 	if err != nil {
 		fmt.Println(err)
 		return
@@ -772,6 +981,14 @@ func RegisterCompany(dynamoClient *dynamodb.Client, EDINETCode string, companyNa
 			company.PL = 1
 		}
 
+		if DryRun == "true" {
+			err = PutDryRunItem(dynamoClient, TableName, company.ID, company)
+			if err != nil {
+				fmt.Println("PutDryRunItem err: ", err)
+			}
+			return
+		}
+
 		item, err := attributevalue.MarshalMap(company)
 		if err != nil {
 			fmt.Println("MarshalMap err: ", err)
@@ -873,6 +1090,14 @@ func RegisterFundamental(dynamoClient *dynamodb.Client, docID string, dateKey st
 	// ファイル名
 	fundamentalsFileName := fmt.Sprintf("%s-fundamentals-from-%s-to-%s.json", EDINETCode, fundamental.PeriodStart, fundamental.PeriodEnd)
 	key := fmt.Sprintf("%s/Fundamentals/%s", EDINETCode, fundamentalsFileName)
+	if DryRun == "true" {
+		err = PutDryRunObject(BucketName, key, fundamentalBody)
+		if err != nil {
+			ErrMsg = "fundamentals ファイルの dry-run 書き出しエラー: "
+			RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+		}
+		return
+	}
 	// ファイルの存在チェック
 	existsFile, _ := S3Client.HeadObject(context.TODO(), &s3.HeadObjectInput{
 		Bucket: aws.String(BucketName),
@@ -922,252 +1147,309 @@ func ValidateFundamentals(fundamental Fundamental) bool {
 	return false
 }
 
+// 各財務諸表の TextBlock
+type StatementMatches struct {
+	ConsolidatedBS       string // 連結貸借対照表
+	ConsolidatedBSIFRS   string // 連結貸借対照表（IFRS）= 連結財政状態計算書
+	SoloBS               string // 貸借対照表
+	ConsolidatedPL       string // 連結損益計算書
+	ConsolidatedPLIFRS   string // 連結損益計算書（IFRS）
+	SoloPL               string // 損益計算書
+	ConsolidatedPLCI     string // 連結損益及び包括利益計算書
+	ConsolidatedPLCIIFRS string // 連結純損益及びその他の包括利益計算書 (IFRS)
+	ConsolidatedCI       string // 連結包括利益計算書
+	ConsolidatedCIIFRS   string // 連結包括利益計算書 (IFRS)
+	ConsolidatedCF       string // 連結キャッシュ・フロー計算書
+	ConsolidatedCFIFRS   string // 連結キャッシュ・フロー計算書 (IFRS)
+	SoloCF               string // キャッシュ・フロー計算書
+	SoloCFIFRS           string // キャッシュ・フロー計算書 (IFRS)
+	ConsolidatedSS       string // 連結株主資本等変動計算書
+	ConsolidatedSSIFRS   string // 連結持分変動計算書 (IFRS)
+	SoloSS               string // 株主資本等変動計算書
+	FromIXBRL            bool   // インライン XBRL から取得した場合は true (エスケープされていない HTML)
+}
+
+/*
+XBRL ファイルから各財務諸表の TextBlock を抽出する
+*/
+func FindStatementMatches(body string) StatementMatches {
+	var matches StatementMatches
+
+	// 【連結貸借対照表】
+	consolidatedBSPattern := `(?s)<jpcrp_cor:ConsolidatedBalanceSheetTextBlock contextRef="CurrentYearDuration">(.*?)</jpcrp_cor:ConsolidatedBalanceSheetTextBlock>`
+	consolidatedBSRe := regexp.MustCompile(consolidatedBSPattern)
+	matches.ConsolidatedBS = consolidatedBSRe.FindString(body)
+
+	// 【連結貸借対照表（IFRS）】※ 【連結財政状態計算書】が正式名称
+	consolidatedBSIFRSPattern := `(?s) <jpigp_cor:ConsolidatedStatementOfFinancialPositionIFRSTextBlock contextRef="CurrentYearDuration">(.*?)</jpigp_cor:ConsolidatedStatementOfFinancialPositionIFRSTextBlock>`
+	consolidatedBSIFRSRe := regexp.MustCompile(consolidatedBSIFRSPattern)
+	matches.ConsolidatedBSIFRS = consolidatedBSIFRSRe.FindString(body)
+
+	// 【貸借対照表】
+	soloBSPattern := `(?s)<jpcrp_cor:BalanceSheetTextBlock contextRef="CurrentYearDuration">(.*?)</jpcrp_cor:BalanceSheetTextBlock>`
+	soloBSRe := regexp.MustCompile(soloBSPattern)
+	matches.SoloBS = soloBSRe.FindString(body)
+
+	// 【連結損益計算書】
+	consolidatedPLPattern := `(?s)<jpcrp_cor:ConsolidatedStatementOfIncomeTextBlock contextRef="CurrentYearDuration">(.*?)</jpcrp_cor:ConsolidatedStatementOfIncomeTextBlock>`
+	consolidatedPLRe := regexp.MustCompile(consolidatedPLPattern)
+	matches.ConsolidatedPL = consolidatedPLRe.FindString(body)
+
+	// 【連結損益計算書（IFRS）】
+	consolidatedPLIFRSPattern := `(?s)<jpigp_cor:ConsolidatedStatementOfProfitOrLossIFRSTextBlock contextRef="CurrentYearDuration">(.*?)</jpigp_cor:ConsolidatedStatementOfProfitOrLossIFRSTextBlock>`
+	consolidatedPLIFRSRe := regexp.MustCompile(consolidatedPLIFRSPattern)
+	matches.ConsolidatedPLIFRS = consolidatedPLIFRSRe.FindString(body)
+
+	// 【損益計算書】
+	soloPLPattern := `(?s)<jpcrp_cor:StatementOfIncomeTextBlock contextRef="CurrentYearDuration">(.*?)</jpcrp_cor:StatementOfIncomeTextBlock>`
+	soloPLRe := regexp.MustCompile(soloPLPattern)
+	matches.SoloPL = soloPLRe.FindString(body)
+
+	// 【連結損益及び包括利益計算書】
+	consolidatedPLCIPattern := `(?s)<jpcrp_cor:ConsolidatedStatementOfComprehensiveIncomeSingleStatementTextBlock contextRef="CurrentYearDuration">(.*?)</jpcrp_cor:ConsolidatedStatementOfComprehensiveIncomeSingleStatementTextBlock>`
+	consolidatedPLCIRe := regexp.MustCompile(consolidatedPLCIPattern)
+	matches.ConsolidatedPLCI = consolidatedPLCIRe.FindString(body)
+
+	// 【連結純損益及びその他の包括利益計算書 (IFRS)】
+	consolidatedPLCIIFRSPattern := `(?s)<jpigp_cor:ConsolidatedStatementOfComprehensiveIncomeSingleStatementIFRSTextBlock contextRef="CurrentYearDuration">(.*?)</jpigp_cor:ConsolidatedStatementOfComprehensiveIncomeSingleStatementIFRSTextBlock>`
+	consolidatedPLCIIFRSRe := regexp.MustCompile(consolidatedPLCIIFRSPattern)
+	matches.ConsolidatedPLCIIFRS = consolidatedPLCIIFRSRe.FindString(body)
+
+	// 【連結包括利益計算書】
+	consolidatedCIPattern := `(?s)<jpcrp_cor:ConsolidatedStatementOfComprehensiveIncomeTextBlock contextRef="CurrentYearDuration">(.*?)</jpcrp_cor:ConsolidatedStatementOfComprehensiveIncomeTextBlock>`
+	consolidatedCIRe := regexp.MustCompile(consolidatedCIPattern)
+	matches.ConsolidatedCI = consolidatedCIRe.FindString(body)
+
+	// 【連結包括利益計算書 (IFRS)】
+	consolidatedCIIFRSPattern := `(?s)<jpigp_cor:ConsolidatedStatementOfComprehensiveIncomeIFRSTextBlock contextRef="CurrentYearDuration">(.*?)</jpigp_cor:ConsolidatedStatementOfComprehensiveIncomeIFRSTextBlock>`
+	consolidatedCIIFRSRe := regexp.MustCompile(consolidatedCIIFRSPattern)
+	matches.ConsolidatedCIIFRS = consolidatedCIIFRSRe.FindString(body)
+
+	// 【連結キャッシュ・フロー計算書】
+	consolidatedCFPattern := `(?s)<jpcrp_cor:ConsolidatedStatementOfCashFlowsTextBlock contextRef="CurrentYearDuration">(.*?)</jpcrp_cor:ConsolidatedStatementOfCashFlowsTextBlock>`
+	consolidatedCFRe := regexp.MustCompile(consolidatedCFPattern)
+	matches.ConsolidatedCF = consolidatedCFRe.FindString(body)
+
+	// 【連結キャッシュ・フロー計算書 (IFRS)】
+	consolidatedCFIFRSPattern := `(?s)<jpigp_cor:ConsolidatedStatementOfCashFlowsIFRSTextBlock contextRef="CurrentYearDuration">(.*?)</jpigp_cor:ConsolidatedStatementOfCashFlowsIFRSTextBlock>`
+	consolidatedCFIFRSRe := regexp.MustCompile(consolidatedCFIFRSPattern)
+	matches.ConsolidatedCFIFRS = consolidatedCFIFRSRe.FindString(body)
+
+	// 【キャッシュ・フロー計算書】
+	soloCFPattern := `(?s)<jpcrp_cor:StatementOfCashFlowsTextBlock contextRef="CurrentYearDuration">(.*?)</jpcrp_cor:StatementOfCashFlowsTextBlock>`
+	soloCFRe := regexp.MustCompile(soloCFPattern)
+	matches.SoloCF = soloCFRe.FindString(body)
+
+	// 【キャッシュ・フロー計算書 (IFRS)】
+	soloCFIFRSPattern := `(?s)<jpcrp_cor:StatementOfCashFlowsIFRSTextBlock contextRef="CurrentYearDuration">(.*?)</jpcrp_cor:StatementOfCashFlowsIFRSTextBlock>`
+	soloCFIFRSRe := regexp.MustCompile(soloCFIFRSPattern)
+	matches.SoloCFIFRS = soloCFIFRSRe.FindString(body)
+
+	// 【連結株主資本等変動計算書】
+	consolidatedSSPattern := `(?s)<jpcrp_cor:ConsolidatedStatementOfChangesInEquityTextBlock contextRef="CurrentYearDuration">(.*?)</jpcrp_cor:ConsolidatedStatementOfChangesInEquityTextBlock>`
+	consolidatedSSRe := regexp.MustCompile(consolidatedSSPattern)
+	matches.ConsolidatedSS = consolidatedSSRe.FindString(body)
+
+	// 【連結持分変動計算書 (IFRS)】
+	consolidatedSSIFRSPattern := `(?s)<jpigp_cor:ConsolidatedStatementOfChangesInEquityIFRSTextBlock contextRef="CurrentYearDuration">(.*?)</jpigp_cor:ConsolidatedStatementOfChangesInEquityIFRSTextBlock>`
+	consolidatedSSIFRSRe := regexp.MustCompile(consolidatedSSIFRSPattern)
+	matches.ConsolidatedSSIFRS = consolidatedSSIFRSRe.FindString(body)
+
+	// 【株主資本等変動計算書】
+	soloSSPattern := `(?s)<jpcrp_cor:StatementOfChangesInEquityTextBlock contextRef="CurrentYearDuration">(.*?)</jpcrp_cor:StatementOfChangesInEquityTextBlock>`
+	soloSSRe := regexp.MustCompile(soloSSPattern)
+	matches.SoloSS = soloSSRe.FindString(body)
+
+	return matches
+}
+
 /*
-HTMLをパースしローカルに保存する
+優先順位に従ってパース対象の TextBlock を選ぶ
+対象がない場合は空文字を返す
 @params
 
-		fileType:                  BS もしくは PL
-		body:                      ファイルボディ
-		consolidatedBSMatches:     連結貸借対照表
-		consolidatedBSIFRSMatches: 連結貸借対照表（IFRS）= 連結財政状態計算書
-		soloBSMatches:             貸借対照表
-		consolidatedPLMatches:     連結損益計算書
-		consolidatedPLIFRSMatches: 連結損益計算書（IFRS）
-		soloPLMatches:             損益計算書
-	  BSFileNamePattern:         BSファイル名パターン
-		PLFileNamePattern:         PLファイル名パターン
-*/
-func CreateHTML(docID string, dateKey string, fileType, consolidatedBSMatches, consolidatedBSIFRSMatches, soloBSMatches, consolidatedPLMatches, consolidatedPLIFRSMatches, soloPLMatches, BSFileNamePattern, PLFileNamePattern string) (*goquery.Document, error) {
-	// エスケープ文字をデコード
-	var unescapedStr string
+	fileType: BS, PL, CF, SS, CI のいずれか
+	matches:  FindStatementMatches の結果
 
-	// BS の場合
-	if fileType == "BS" {
-		if consolidatedBSMatches == "" && soloBSMatches == "" {
-			RegisterFailedJson(docID, dateKey, "parse 対象の貸借対照表データがありません")
-			return nil, errors.New("parse 対象の貸借対照表データがありません")
-		} else if consolidatedBSIFRSMatches != "" {
+@returns
+
+	label: 選ばれた財務諸表の名称
+	match: 選ばれた TextBlock
+*/
+func SelectStatementMatch(fileType string, matches StatementMatches) (string, string) {
+	switch fileType {
+	case "BS":
+		if matches.ConsolidatedBS == "" && matches.SoloBS == "" {
+			return "", ""
+		} else if matches.ConsolidatedBSIFRS != "" {
 			// 優先順位1: 連結貸借対照表（IFRS）= 連結財政状態計算書
-			fmt.Println("連結財政状態計算書 に該当する箇所があります⭐️")
-			unescapedStr = html.UnescapeString(consolidatedBSIFRSMatches)
-		} else if consolidatedBSMatches != "" {
+			return "連結財政状態計算書", matches.ConsolidatedBSIFRS
+		} else if matches.ConsolidatedBS != "" {
 			// 優先順位2: 連結貸借対照表
-			unescapedStr = html.UnescapeString(consolidatedBSMatches)
-		} else if soloBSMatches != "" {
-			// 優先順位3: 単独貸借対照表
-			unescapedStr = html.UnescapeString(soloBSMatches)
+			return "連結貸借対照表", matches.ConsolidatedBS
 		}
-	}
-
-	// PL の場合
-	if fileType == "PL" {
-		if consolidatedPLMatches == "" && soloPLMatches == "" {
-			RegisterFailedJson(docID, dateKey, "parse 対象の損益計算書データがありません")
-			return nil, errors.New("parse 対象の損益計算書データがありません")
-		} else if consolidatedPLIFRSMatches != "" {
+		// 優先順位3: 単独貸借対照表
+		return "貸借対照表", matches.SoloBS
+	case "PL":
+		if matches.ConsolidatedPLIFRS != "" {
 			// 優先順位1: 連結損益計算書（IFRS）
-			unescapedStr = html.UnescapeString(consolidatedPLIFRSMatches)
-		} else if consolidatedPLMatches != "" {
-			// 優先順位2: 連結損益計算書
-			unescapedStr = html.UnescapeString(consolidatedPLMatches)
-		} else if soloPLMatches != "" {
-			// 優先順位3: 単独損益計算書
-			unescapedStr = html.UnescapeString(soloPLMatches)
-		}
+			return "連結損益計算書（IFRS）", matches.ConsolidatedPLIFRS
+		} else if matches.ConsolidatedPLCIIFRS != "" {
+			// 優先順位2: 連結純損益及びその他の包括利益計算書（IFRS）
+			return "連結純損益及びその他の包括利益計算書（IFRS）", matches.ConsolidatedPLCIIFRS
+		} else if matches.ConsolidatedPL != "" {
+			// 優先順位3: 連結損益計算書
+			return "連結損益計算書", matches.ConsolidatedPL
+		} else if matches.ConsolidatedPLCI != "" {
+			// 優先順位4: 連結損益及び包括利益計算書 (連結損益計算書がない場合)
+			return "連結損益及び包括利益計算書", matches.ConsolidatedPLCI
+		} else if matches.SoloPL != "" {
+			// 優先順位5: 単独損益計算書
+			return "損益計算書", matches.SoloPL
+		}
+	case "CI":
+		if matches.ConsolidatedCIIFRS != "" {
+			// 優先順位1: 連結包括利益計算書 (IFRS)
+			return "連結包括利益計算書（IFRS）", matches.ConsolidatedCIIFRS
+		} else if matches.ConsolidatedPLCIIFRS != "" {
+			// 優先順位2: 連結純損益及びその他の包括利益計算書（IFRS）
+			return "連結純損益及びその他の包括利益計算書（IFRS）", matches.ConsolidatedPLCIIFRS
+		} else if matches.ConsolidatedCI != "" {
+			// 優先順位3: 連結包括利益計算書
+			return "連結包括利益計算書", matches.ConsolidatedCI
+		} else if matches.ConsolidatedPLCI != "" {
+			// 優先順位4: 連結損益及び包括利益計算書
+			return "連結損益及び包括利益計算書", matches.ConsolidatedPLCI
+		}
+	case "CF":
+		if matches.ConsolidatedCFIFRS != "" {
+			// 優先順位1: 連結キャッシュ・フロー計算書 (IFRS)
+			return "連結キャッシュ・フロー計算書 (IFRS)", matches.ConsolidatedCFIFRS
+		} else if matches.ConsolidatedCF != "" {
+			// 優先順位2: 連結キャッシュ・フロー計算書
+			return "連結キャッシュ・フロー計算書", matches.ConsolidatedCF
+		} else if matches.SoloCFIFRS != "" {
+			// 優先順位3: 単独キャッシュ・フロー計算書（IFRS）
+			return "キャッシュ・フロー計算書 (IFRS)", matches.SoloCFIFRS
+		} else if matches.SoloCF != "" {
+			// 優先順位4: 単独キャッシュ・フロー計算書
+			return "キャッシュ・フロー計算書", matches.SoloCF
+		}
+	case "SS":
+		if matches.ConsolidatedSSIFRS != "" {
+			// 優先順位1: 連結持分変動計算書 (IFRS)
+			return "連結持分変動計算書", matches.ConsolidatedSSIFRS
+		} else if matches.ConsolidatedSS != "" {
+			// 優先順位2: 連結株主資本等変動計算書
+			return "連結株主資本等変動計算書", matches.ConsolidatedSS
+		} else if matches.SoloSS != "" {
+			// 優先順位3: 単独株主資本等変動計算書
+			return "株主資本等変動計算書", matches.SoloSS
+		}
+	}
+	return "", ""
+}
+
+/*
+選ばれた TextBlock を S3 に送信する HTML に変換する
+インライン XBRL から取得した TextBlock はエスケープされていないため整形のみ行う
+*/
+func (m StatementMatches) StatementHTML(match string) string {
+	if m.FromIXBRL {
+		return FormatHtmlTable(match)
 	}
+	return UnescapeTextBlock(match)
+}
 
+/*
+TextBlock のエスケープ文字をデコードし、HTML テーブルを整形する
+*/
+func UnescapeTextBlock(match string) string {
+	unescapedStr := html.UnescapeString(match)
 	// デコードしきれていない文字は replace
 	// 特定のエンティティをさらに手動でデコード
 	unescapedStr = strings.ReplaceAll(unescapedStr, "&apos;", "'")
 
 	// HTMLデータを加工
-	unescapedStr = FormatHtmlTable(unescapedStr)
+	return FormatHtmlTable(unescapedStr)
+}
 
-	// html ファイルとして書き出す
-	// Lambda 用に /tmp を足す
-	HTMLDirName := filepath.Join("/tmp", "HTML")
-	var fileName string
-	var filePath string
+/*
+HTMLをパースする
+@params
 
-	if fileType == "BS" {
-		fileName = fmt.Sprintf("%s.html", BSFileNamePattern)
-		filePath = filepath.Join(HTMLDirName, fileName)
-	}
+	fileType: BS もしくは PL
+	matches:  各財務諸表の TextBlock
 
-	if fileType == "PL" {
-		fileName = fmt.Sprintf("%s.html", PLFileNamePattern)
-		filePath = filepath.Join(HTMLDirName, fileName)
-	}
+@returns
 
-	// HTMLディレクトリが存在するか確認
-	if _, err := os.Stat(HTMLDirName); os.IsNotExist(err) {
-		// ディレクトリが存在しない場合は作成
-		err := os.Mkdir(HTMLDirName, 0755) // 0755はディレクトリのパーミッション
-		if err != nil {
-			ErrMsg = "HTML ローカルディレクトリ作成エラー: "
-			RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
-			fmt.Println("Error creating HTML directory:", err)
-			return nil, err
-		}
-	}
+	doc:      パースした HTML (UpdateEverySummary に渡す)
+	HTMLBody: S3 に送信する HTML
+*/
+func CreateHTML(docID string, dateKey string, fileType string, matches StatementMatches) (*goquery.Document, []byte, error) {
+	label, match := SelectStatementMatch(fileType, matches)
 
-	createFile, err := os.Create(filePath)
-	if err != nil {
-		ErrMsg = "HTML ローカルファイル作成エラー: "
-		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
-		// fmt.Println("HTML create err: ", err)
-		return nil, err
+	// BS の場合
+	if fileType == "BS" && match == "" {
+		RegisterFailedJson(docID, dateKey, "parse 対象の貸借対照表データがありません")
+		return nil, nil, errors.New("parse 対象の貸借対照表データがありません")
 	}
-	defer createFile.Close()
 
-	_, err = createFile.WriteString(unescapedStr)
-	if err != nil {
-		ErrMsg = "HTML ローカルファイル書き込みエラー: "
-		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
-		// fmt.Println("HTML write err: ", err)
-		return nil, err
+	// PL の場合
+	if fileType == "PL" && match == "" {
+		RegisterFailedJson(docID, dateKey, "parse 対象の損益計算書データがありません")
+		return nil, nil, errors.New("parse 対象の損益計算書データがありません")
 	}
 
-	openFile, err := os.Open(filePath)
-	if err != nil {
-		ErrMsg = "HTML ローカルファイル書き込み後オープンエラー: "
-		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
-		// fmt.Println("HTML open error: ", err)
-		return nil, err
+	if label == "連結財政状態計算書" {
+		fmt.Println("連結財政状態計算書 に該当する箇所があります⭐️")
 	}
-	defer openFile.Close()
+
+	// エスケープ文字をデコード
+	HTMLBody := []byte(matches.StatementHTML(match))
 
 	// goqueryでHTMLをパース
-	doc, err := goquery.NewDocumentFromReader(openFile)
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(HTMLBody))
 	if err != nil {
 		ErrMsg = "HTML goquery.NewDocumentFromReader error: "
 		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
-		// fmt.Println("HTML goquery.NewDocumentFromReader error: ", err)
-		return nil, err
+		return nil, nil, err
 	}
 	// return した doc は updateSummary に渡す
-	return doc, nil
+	return doc, HTMLBody, nil
 }
 
 // CF計算書登録処理
 /*
-cfFileNamePattern:          ファイル名のパターン
-body:                       文字列に変換したXBRLファイル
-consolidatedCFMattches:     連結キャッシュ・フロー計算書
-consolidatedCFIFRSMattches: 連結キャッシュ・フロー計算書 (IFRS)
-soloCFMattches:             キャッシュ・フロー計算書
-soloCFIFRSPattern:          キャッシュ・フロー計算書 (IFRS)
+matches: 各財務諸表の TextBlock
 */
-func CreateCFHTML(docID string, dateKey string, cfFileNamePattern, body string, consolidatedCFMattches string, consolidatedCFIFRSMattches string, soloCFMattches string, soloCFIFRSMattches string) (*goquery.Document, error) {
-
-	if consolidatedCFMattches == "" && consolidatedCFIFRSMattches == "" && soloCFMattches == "" && soloCFIFRSMattches == "" {
+func CreateCFHTML(docID string, dateKey string, matches StatementMatches) (*goquery.Document, []byte, error) {
+	_, match := SelectStatementMatch("CF", matches)
+	if match == "" {
 		RegisterFailedJson(docID, dateKey, "パースする対象がありません")
-		return nil, errors.New("パースする対象がありません")
-	}
-
-	var match string
-
-	if consolidatedCFIFRSMattches != "" {
-		// 優先順位1: 連結キャッシュ・フロー計算書 (IFRS)
-		match = consolidatedCFIFRSMattches
-	} else if consolidatedCFMattches != "" {
-		// 優先順位2: 連結キャッシュ・フロー計算書
-		match = consolidatedCFMattches
-	} else if soloCFIFRSMattches != "" {
-		// 優先順位3: 単独キャッシュ・フロー計算書（IFRS）
-		match = soloCFIFRSMattches
-	} else if soloCFMattches != "" {
-		// 優先順位3: 単独キャッシュ・フロー計算書
-		match = soloCFMattches
-	}
-
-	unescapedMatch := html.UnescapeString(match)
-	// デコードしきれていない文字は replace
-	// 特定のエンティティをさらに手動でデコード
-	unescapedMatch = strings.ReplaceAll(unescapedMatch, "&apos;", "'")
-	unescapedMatch = FormatHtmlTable(unescapedMatch)
-
-	// Lambda 用に /tmp を足す
-	HTMLDirName := filepath.Join("/tmp", "HTML")
-	cfHTMLFileName := fmt.Sprintf("%s.html", cfFileNamePattern)
-	cfHTMLFilePath := filepath.Join(HTMLDirName, cfHTMLFileName)
-
-	// HTML ファイルの作成
-	cfHTML, err := os.Create(cfHTMLFilePath)
-	if err != nil {
-		ErrMsg = "CF HTML create err: "
-		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
-		return nil, err
-	}
-	defer cfHTML.Close()
-
-	// HTML ファイルに書き込み
-	_, err = cfHTML.WriteString(unescapedMatch)
-	if err != nil {
-		ErrMsg = "CF HTML write err: "
-		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
-		return nil, err
+		return nil, nil, errors.New("パースする対象がありません")
 	}
 
-	// HTML ファイルの読み込み
-	cfHTMLFile, err := os.Open(cfHTMLFilePath)
-	if err != nil {
-		ErrMsg = "CF HTML open error: "
-		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
-		return nil, err
-	}
-	defer cfHTMLFile.Close()
+	cfHTMLBody := []byte(matches.StatementHTML(match))
 
 	// goqueryでHTMLをパース
-	cfDoc, err := goquery.NewDocumentFromReader(cfHTMLFile)
+	cfDoc, err := goquery.NewDocumentFromReader(bytes.NewReader(cfHTMLBody))
 	if err != nil {
 		ErrMsg = "CF goquery.NewDocumentFromReader err: "
 		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
-		return nil, err
+		return nil, nil, err
 	}
-	return cfDoc, nil
+	return cfDoc, cfHTMLBody, nil
 }
 
-func CreateJSON(docID string, dateKey string, fileNamePattern string, summary interface{}) (string, error) {
-	fileName := fmt.Sprintf("%s.json", fileNamePattern)
-	// Lambda 用に /tmp を足す
-	// filePath := fmt.Sprintf("json/%s", fileName)
-	jsonDirName := filepath.Join("/tmp", "json")
-	filePath := filepath.Join(jsonDirName, fileName)
-
-	// ディレクトリが存在しない場合は作成
-	// Lambda 用に /tmp を足す
-	err := os.MkdirAll(jsonDirName, os.ModePerm)
-	if err != nil {
-		ErrMsg = "Error creating JSON directory: "
-		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
-		return "", err
-	}
-
-	jsonFile, err := os.Create(filePath)
-	if err != nil {
-		// fmt.Println(err)
-		ErrMsg = "ローカル JSON ファイル作成エラー: "
-		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
-		return "", err
-	}
-	defer jsonFile.Close()
-
+func CreateJSON(docID string, dateKey string, summary interface{}) ([]byte, error) {
 	jsonBody, err := json.MarshalIndent(summary, "", "  ")
 	if err != nil {
-		// fmt.Println(err)
-		ErrMsg = "ローカル JSON MarshalIndent エラー: "
-		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
-		return "", err
-	}
-	_, err = jsonFile.Write(jsonBody)
-	if err != nil {
-		// fmt.Println(err)
-		ErrMsg = "ローカル JSON ファイル write エラー: "
+		ErrMsg = "JSON MarshalIndent エラー: "
 		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
-		return "", err
+		return nil, err
 	}
-	return filePath, nil
+	return jsonBody, nil
 }
 
 func ValidateCFSummary(cfSummary CFSummary) bool {
@@ -1185,6 +1467,23 @@ func ValidateCFSummary(cfSummary CFSummary) bool {
 	return false
 }
 
+/*
+CF 計算書のサマリーにフリーCF を設定し、期首残高 + 増減額 = 期末残高 となっているか確認する
+単位未満の端数処理による差があるため、1 以内の差は一致とみなす
+連結範囲の変動などで一致しない場合もあるため、結果はサマリーの有効・無効には使わない
+*/
+func CompleteCFSummary(cfSummary *CFSummary) {
+	cfSummary.FreeCF = AddTitleValue(cfSummary.OperatingCF, cfSummary.InvestingCF)
+
+	if cfSummary.StartCash == (TitleValue{}) || cfSummary.EndCash == (TitleValue{}) || cfSummary.NetChange == (TitleValue{}) {
+		cfSummary.IsCashReconciled = false
+		return
+	}
+	calculated := AddTitleValue(cfSummary.StartCash, cfSummary.NetChange)
+	cfSummary.IsCashReconciled = withinTolerance(calculated.Previous, cfSummary.EndCash.Previous, 1) &&
+		withinTolerance(calculated.Current, cfSummary.EndCash.Current, 1)
+}
+
 func PrintValidatedSummaryMsg(companyName string, fileName string, summary interface{}, isValid bool) {
 	var summaryType string
 
@@ -1195,6 +1494,10 @@ func PrintValidatedSummaryMsg(companyName string, fileName string, summary inter
 		summaryType = "損益計算書"
 	case CFSummary:
 		summaryType = "CF計算書"
+	case FundSummary:
+		summaryType = "投資信託"
+	case SSSummary:
+		summaryType = "株主資本等変動計算書"
 	}
 
 	jsonBody, _ := json.MarshalIndent(summary, "", "  ")
@@ -1214,59 +1517,33 @@ func PrintValidatedSummaryMsg(companyName string, fileName string, summary inter
 }
 
 // 汎用ファイル送信処理
-func PutFileToS3(docID string, dateKey string, EDINETCode string, companyName string, fileNamePattern string, extension string, objectKeys []string, wg *sync.WaitGroup) {
+/*
+body: 送信するファイルの中身 (CreateHTML, CreateJSON などで作成したもの)
+*/
+func PutFileToS3(docID string, dateKey string, EDINETCode string, companyName string, fileNamePattern string, extension string, body []byte, objectKeys []string, wg *sync.WaitGroup) {
+	PutScopedFileToS3(docID, dateKey, EDINETCode, companyName, "", fileNamePattern, extension, body, objectKeys, wg)
+}
+
+/*
+連結・個別ごとのファイル送信処理
+scope が空文字でない場合は {EDINETコード}/{BS, PL, CF}/{scope}/ 配下に登録する
+*/
+func PutScopedFileToS3(docID string, dateKey string, EDINETCode string, companyName string, scope string, fileNamePattern string, extension string, body []byte, objectKeys []string, wg *sync.WaitGroup) {
 	// 並列で処理する場合
 	if Parallel == "true" {
 		defer wg.Done()
 	}
 
-	var fileName string
-	var filePath string
-
-	switch extension {
-	case "json":
-		fileName = fmt.Sprintf("%s.json", fileNamePattern)
-		// Lambda 用に /tmp を足す
-		// filePath = fmt.Sprintf("json/%s", fileName)
-		filePath = filepath.Join("/tmp", "json", fileName)
-	case "html":
-		fileName = fmt.Sprintf("%s.html", fileNamePattern)
-		// Lambda 用に /tmp を足す
-		// filePath = fmt.Sprintf("HTML/%s", fileName)
-		filePath = filepath.Join("/tmp", "HTML", fileName)
-	}
-
-	// 処理後、ローカルファイルを削除
-	defer func() {
-		err := os.RemoveAll(filePath)
-		if err != nil {
-			ErrMsg = "ローカルファイル削除エラー: "
-			RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
-			return
-		}
-		// fmt.Printf("%s を削除しました\n", filePath)
-	}()
-
-	// S3 に ファイルを送信 (Key は aws configure で設定しておく)
-	file, err := os.Open(filePath)
-	if err != nil {
-		ErrMsg = "S3 へのファイル送信時、ローカルファイル open エラー: "
-		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
-		return
-	}
-	defer func() {
-		err := file.Close()
-		if err != nil {
-			ErrMsg = "S3 へのファイル送信時、ローカルファイル close エラー: "
-			RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
-			return
-		}
-	}()
+	fileName := fmt.Sprintf("%s.%s", fileNamePattern, extension)
 
 	splitByHyphen := strings.Split(fileName, "-")
 	if len(splitByHyphen) >= 3 {
 		reportType := splitByHyphen[2] // BS or PL or CF
-		key := fmt.Sprintf("%s/%s/%s", EDINETCode, reportType, fileName)
+		keyPrefix := fmt.Sprintf("%s/%s", EDINETCode, reportType)
+		if scope != "" {
+			keyPrefix = fmt.Sprintf("%s/%s", keyPrefix, scope)
+		}
+		key := fmt.Sprintf("%s/%s", keyPrefix, fileName)
 
 		contentType, err := GetContentType(docID, dateKey, extension)
 		if err != nil {
@@ -1283,7 +1560,14 @@ func PutFileToS3(docID string, dateKey string, EDINETCode string, companyName st
 			if len(splitBySlash) >= 1 {
 				if len(objectKeys) > 0 {
 					for _, objectKey := range objectKeys {
-						if objectKey != key && strings.Contains(objectKey, fromToMatch) {
+						// 同じディレクトリのファイル (連結・個別に分ける前のファイルを含む) のみ削除する
+						objectDir := path.Dir(objectKey)
+						isSameDir := objectDir == keyPrefix || (scope != "" && objectDir == path.Dir(keyPrefix))
+						if objectKey != key && isSameDir && strings.Contains(objectKey, fromToMatch) {
+							if DryRun == "true" {
+								DeleteDryRunObject(BucketName, objectKey)
+								continue
+							}
 							// 同じ期間の古いファイルを S3 から削除
 							_, err := S3Client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
 								Bucket: aws.String(BucketName),
@@ -1300,6 +1584,15 @@ func PutFileToS3(docID string, dateKey string, EDINETCode string, companyName st
 			}
 		}
 
+		if DryRun == "true" {
+			err = PutDryRunObject(BucketName, key, body)
+			if err != nil {
+				ErrMsg = "dry-run 書き出しエラー: "
+				RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+			}
+			return
+		}
+
 		// 同名ファイルの存在チェック
 		existsFile, err := CheckFileExists(S3Client, BucketName, key)
 		if err != nil {
@@ -1314,7 +1607,7 @@ func PutFileToS3(docID string, dateKey string, EDINETCode string, companyName st
 			_, err = S3Client.PutObject(context.TODO(), &s3.PutObjectInput{
 				Bucket:      aws.String(BucketName),
 				Key:         aws.String(key),
-				Body:        file,
+				Body:        bytes.NewReader(body),
 				ContentType: aws.String(contentType),
 			})
 			if err != nil {
@@ -1332,6 +1625,24 @@ func PutFileToS3(docID string, dateKey string, EDINETCode string, companyName st
 				reportTypeStr = "損益計算書"
 			case "CF":
 				reportTypeStr = "CF計算書"
+			case "SS":
+				reportTypeStr = "株主資本等変動計算書"
+			case "CI":
+				reportTypeStr = "包括利益計算書"
+			case "Audit":
+				reportTypeStr = "監査報告書"
+			case "CSVDiff":
+				reportTypeStr = "CSV 差分"
+			case "EN":
+				reportTypeStr = "英文財務諸表"
+			case "Segments":
+				reportTypeStr = "セグメント情報"
+			case "Shareholders":
+				reportTypeStr = "株主・配当情報"
+			case "Governance":
+				reportTypeStr = "役員・ガバナンス情報"
+			case "Fund":
+				reportTypeStr = "投資信託情報"
 			}
 			uploadDoneMsg := fmt.Sprintf("「%s」の%s%sを登録しました ⭕️ (ファイル名: %s)", companyName, reportTypeStr, extension, key)
 			fmt.Println(uploadDoneMsg)
@@ -1352,13 +1663,21 @@ func GetContentType(docID string, dateKey string, extension string) (string, err
 }
 
 func HandleRegisterJSON(docID string, dateKey string, EDINETCode string, companyName string, fileNamePattern string, summary interface{}, objectKeys []string, wg *sync.WaitGroup) {
-	_, err := CreateJSON(docID, dateKey, fileNamePattern, summary)
+	HandleRegisterScopedJSON(docID, dateKey, EDINETCode, companyName, "", fileNamePattern, summary, objectKeys, wg)
+}
+
+func HandleRegisterScopedJSON(docID string, dateKey string, EDINETCode string, companyName string, scope string, fileNamePattern string, summary interface{}, objectKeys []string, wg *sync.WaitGroup) {
+	jsonBody, err := CreateJSON(docID, dateKey, summary)
 	if err != nil {
 		ErrMsg = "CF JSON ファイル作成エラー: "
 		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+		// 並列で処理する場合
+		if Parallel == "true" {
+			wg.Done()
+		}
 		return
 	}
-	PutFileToS3(docID, dateKey, EDINETCode, companyName, fileNamePattern, "json", objectKeys, wg)
+	PutScopedFileToS3(docID, dateKey, EDINETCode, companyName, scope, fileNamePattern, "json", jsonBody, objectKeys, wg)
 }
 
 func FormatUnitStr(baseStr string) string {
@@ -1682,6 +2001,14 @@ func deleteInvalidSummaryJsonItem(docID string, dateKey string, summaryType stri
 }
 
 func PutXBRLtoS3(docID string, dateKey string, key string, body []byte) {
+	if DryRun == "true" {
+		err := PutDryRunObject(EDINETBucketName, key, body)
+		if err != nil {
+			ErrMsg = "XBRL ファイルの dry-run 書き出しエラー: "
+			RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+		}
+		return
+	}
 	// ファイルの存在チェック
 	existsFile, _ := S3Client.HeadObject(context.TODO(), &s3.HeadObjectInput{
 		Bucket: aws.String(EDINETBucketName),
@@ -1703,20 +2030,30 @@ func PutXBRLtoS3(docID string, dateKey string, key string, body []byte) {
 	}
 }
 
-func GetTitleValue(docID string, dateKey string, titleName string, previousText string, currentText string) (TitleValue, error) {
+/*
+エラーの登録先 (通常は RegisterFailedJson)
+*/
+type FailedJsonSink func(docID string, dateKey string, ErrMsg string)
+
+// inspect 時は failed.json に書き込まない (エラーは行のスキップ理由として記録する)
+func discardFailedJson(docID string, dateKey string, ErrMsg string) {}
+
+func GetTitleValue(docID string, dateKey string, titleName string, previousText string, currentText string, registerFailed FailedJsonSink) (TitleValue, error) {
 	previousIntValue, err := ConvertTextValue2IntValue(previousText)
 	if err != nil {
-		if err.Error() != EmptyStrConvErr {
+		// 空欄・数値以外の見出し・小数 (1株当たりの値など) は記録しない
+		if !errors.Is(err, ErrEmptyJPNumber) && !errors.Is(err, ErrInvalidJPNumber) && !errors.Is(err, ErrDecimalJPNumber) {
 			ErrMsg = "ConvertTextValue2IntValue (PL previous) エラー: "
-			RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+			registerFailed(docID, dateKey, ErrMsg+err.Error())
 		}
 		return TitleValue{}, err
 	}
 	currentIntValue, err := ConvertTextValue2IntValue(currentText)
 	if err != nil {
-		if err.Error() != EmptyStrConvErr {
+		// 空欄・数値以外の見出し・小数 (1株当たりの値など) は記録しない
+		if !errors.Is(err, ErrEmptyJPNumber) && !errors.Is(err, ErrInvalidJPNumber) && !errors.Is(err, ErrDecimalJPNumber) {
 			ErrMsg = "ConvertTextValue2IntValue (PL previous) エラー: "
-			RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+			registerFailed(docID, dateKey, ErrMsg+err.Error())
 		}
 		return TitleValue{}, err
 	}
@@ -1726,7 +2063,26 @@ func GetTitleValue(docID string, dateKey string, titleName string, previousText
 	}, nil
 }
 
-func UpdateEverySummary(doc *goquery.Document, docID string, dateKey string, summaryType string, summary *Summary, plSummary *PLSummary, cfSummary *CFSummary, fundamental *Fundamental) {
+/*
+表の行ごとにサマリーの項目を設定する
+@params
+
+	labelDictionary: 勘定科目の辞書 (nil の場合は SummaryLabelDictionary)
+	recorder:        inspect-xbrl の行ごとの記録先 (inspect 時以外は nil)
+*/
+func UpdateEverySummary(doc *goquery.Document, docID string, dateKey string, summaryType string, summary *Summary, plSummary *PLSummary, cfSummary *CFSummary, fundamental *Fundamental, labelDictionary *LabelDictionary, recorder *InspectStatement) {
+	registerFailed := FailedJsonSink(RegisterFailedJson)
+	if recorder != nil {
+		registerFailed = discardFailedJson
+	}
+	// 書類の勘定科目の辞書がない場合は共通の辞書を使う
+	if labelDictionary == nil {
+		labelDictionary = SummaryLabelDictionary()
+	}
+	// 勘定科目の辞書で一致した行 (表全体で管理する)
+	labelMatches := LabelMatchState{}
+	// 見出し行から前期・当期の列が分かる場合は列の位置で値を取得する
+	columns, hasColumns := DetectStatementColumns(doc)
 	doc.Find("tr").Each(func(i int, s *goquery.Selection) {
 		tdText := s.Find("td").Text()
 		tdText = strings.TrimSpace(tdText)
@@ -1738,59 +2094,111 @@ func UpdateEverySummary(doc *goquery.Document, docID string, dateKey string, sum
 			}
 		}
 
+		// inspect-xbrl 用の記録 (inspect 時以外は nil)
+		row := recorder.row(i, titleTexts)
+
 		var titleValue TitleValue
 		var titleName string
 		var err error
 		if len(titleTexts) >= 1 {
 			titleName = titleTexts[0]
 		}
-		if len(titleTexts) >= 4 {
-			titleValue, err = GetTitleValue(docID, dateKey, titleName, titleTexts[2], titleTexts[3])
+		// 見出し行 (金額のない行) で値を上書きしないようにする
+		hasValue := len(titleTexts) >= 3
+		if hasColumns {
+			var previousText, currentText string
+			previousText, currentText, hasValue = columns.CellTexts(s)
+			if hasValue {
+				titleValue, err = GetTitleValue(docID, dateKey, titleName, previousText, currentText, registerFailed)
+				if err != nil {
+					row.skip("数値変換エラー: " + err.Error())
+					return
+				}
+			}
+		} else if len(titleTexts) >= 4 {
+			titleValue, err = GetTitleValue(docID, dateKey, titleName, titleTexts[2], titleTexts[3], registerFailed)
 			if err != nil {
+				row.skip("数値変換エラー: " + err.Error())
 				return
 			}
 		} else if len(titleTexts) >= 3 {
-			titleValue, err = GetTitleValue(docID, dateKey, titleName, titleTexts[1], titleTexts[2])
+			titleValue, err = GetTitleValue(docID, dateKey, titleName, titleTexts[1], titleTexts[2], registerFailed)
 			if err != nil {
+				row.skip("数値変換エラー: " + err.Error())
 				return
 			}
 		}
 
-		if summaryType == "bs" {
+		// 勘定科目の辞書で一致した項目 (金額のない見出し行・内訳の行は除く)
+		labelKey, isLabelMatched := labelDictionary.Match(summaryType, titleName)
+		isLabelMatched = isLabelMatched && hasValue && labelMatches.Accept(labelKey, RowIndent(s), titleName)
 
-			if titleName == "流動資産合計" {
-				summary.CurrentAssets.Previous = titleValue.Previous
-				summary.CurrentAssets.Current = titleValue.Current
-			}
-			if titleName == "有形固定資産合計" {
-				summary.TangibleAssets.Previous = titleValue.Previous
-				summary.TangibleAssets.Current = titleValue.Current
-			}
-			if titleName == "無形固定資産合計" {
-				summary.IntangibleAssets.Previous = titleValue.Previous
-				summary.IntangibleAssets.Current = titleValue.Current
+		if summaryType == "bs" {
+			if isLabelMatched {
+				switch labelKey {
+				case "current_assets":
+					summary.CurrentAssets = titleValue
+				case "tangible_assets":
+					summary.TangibleAssets = titleValue
+				case "intangible_assets":
+					summary.IntangibleAssets = titleValue
+				case "investments_and_other_assets":
+					summary.InvestmentsAndOtherAssets = titleValue
+				case "current_liabilities":
+					summary.CurrentLiabilities = titleValue
+				case "fixed_liabilities":
+					summary.FixedLiabilities = titleValue
+				case "net_assets":
+					summary.NetAssets = titleValue
+					// fundamental
+					fundamental.NetAssets = titleValue.Current
+				case "liabilities":
+					summary.Liabilities = titleValue
+					// fundamental
+					fundamental.Liabilities = titleValue.Current
+					row.assign("fundamental.liabilities", titleValue)
+				case "total_assets":
+					summary.TotalAssets = titleValue
+				case "shareholders_equity":
+					summary.ShareholdersEquity = titleValue
+				case "retained_earnings":
+					summary.RetainedEarnings = titleValue
+				case "treasury_stock":
+					summary.TreasuryStock = titleValue
+				case "non_controlling_interests":
+					summary.NonControllingInterests = titleValue
+				case "subscription_rights":
+					summary.SubscriptionRights = titleValue
+				case "owners_equity":
+					summary.OwnersEquity = titleValue
+				case "cash_and_deposits":
+					summary.CashAndDeposits = titleValue
+				case "inventories":
+					summary.Inventories = titleValue
+				case "short_term_borrowings":
+					summary.ShortTermBorrowings = titleValue
+				}
+				row.assign(labelKey, titleValue)
 			}
-			if titleName == "投資その他の資産合計" {
-				summary.InvestmentsAndOtherAssets.Previous = titleValue.Previous
-				summary.InvestmentsAndOtherAssets.Current = titleValue.Current
+
+			// 合計行がなく内訳の行を合計する項目
+			// 棚卸資産は内訳で表示されている場合は合計する
+			if slices.Contains(InventoryTitles, titleName) && hasValue {
+				summary.Inventories = AddTitleValue(summary.Inventories, titleValue)
+				row.assign("inventories", titleValue)
 			}
-			if titleName == "流動負債合計" {
-				summary.CurrentLiabilities.Previous = titleValue.Previous
-				summary.CurrentLiabilities.Current = titleValue.Current
+			if slices.Contains(LongTermBorrowingsTitles, titleName) && hasValue {
+				summary.LongTermBorrowings = AddTitleValue(summary.LongTermBorrowings, titleValue)
+				row.assign("long_term_borrowings", titleValue)
 			}
-			if titleName == "固定負債合計" {
-				summary.FixedLiabilities.Previous = titleValue.Previous
-				summary.FixedLiabilities.Current = titleValue.Current
+			if slices.Contains(BondsTitles, titleName) && hasValue {
+				summary.Bonds = AddTitleValue(summary.Bonds, titleValue)
+				row.assign("bonds", titleValue)
 			}
-			if titleName == "純資産合計" {
-				summary.NetAssets.Previous = titleValue.Previous
-				summary.NetAssets.Current = titleValue.Current
-				// fundamental
-				fundamental.NetAssets = titleValue.Current
-			}
-			if titleName == "負債合計" {
-				// fundamental
-				fundamental.Liabilities = titleValue.Current
+			// IFRS では流動負債・非流動負債の両方に表示される
+			if titleName == "社債及び借入金" && hasValue {
+				summary.BondsAndBorrowings = AddTitleValue(summary.BondsAndBorrowings, titleValue)
+				row.assign("bonds_and_borrowings", titleValue)
 			}
 
 			if len(splitTdTexts) == 1 && titleTexts != nil && strings.Contains(titleTexts[0], "単位：") && summary.UnitString == "" {
@@ -1802,70 +2210,97 @@ func UpdateEverySummary(doc *goquery.Document, docID string, dateKey string, sum
 				splitUnitStrs := strings.Split(baseStr, "：")
 				if len(splitUnitStrs) >= 2 {
 					summary.UnitString = splitUnitStrs[1]
+					row.assignText("unit_string", summary.UnitString)
 				}
 			}
 		} else if summaryType == "pl" {
-			// 営業収益合計の設定が終わったかどうか管理するフラグ
-			isOperatingRevenueDone := false
-			// 営業費用合計の設定が終わったかどうか管理するフラグ
-			isOperatingCostDone := false
-
-			if strings.Contains(titleName, "売上原価") {
-				plSummary.CostOfGoodsSold.Previous = titleValue.Previous
-				plSummary.CostOfGoodsSold.Current = titleValue.Current
+			if isLabelMatched {
+				switch labelKey {
+				case "cost_of_goods_sold":
+					plSummary.CostOfGoodsSold = titleValue
+				case "sg_and_a":
+					plSummary.SGAndA = titleValue
+				case "sales":
+					plSummary.Sales = titleValue
+					// fundamental
+					fundamental.Sales = titleValue.Current
+				case "operating_profit":
+					plSummary.OperatingProfit = titleValue
+					// fundamental
+					fundamental.OperatingProfit = titleValue.Current
+				case "operating_revenue":
+					plSummary.HasOperatingRevenue = true
+					plSummary.OperatingRevenue = titleValue
+					// fundamental
+					fundamental.HasOperatingRevenue = true
+					fundamental.OperatingRevenue = titleValue.Current
+				case "operating_cost":
+					plSummary.HasOperatingCost = true
+					plSummary.OperatingCost = titleValue
+					// fundamental
+					fundamental.HasOperatingCost = true
+					fundamental.OperatingCost = titleValue.Current
+				case "ordinary_profit":
+					plSummary.OrdinaryProfit = titleValue
+				}
+				row.assign(labelKey, titleValue)
 			}
-			if strings.Contains(titleName, "販売費及び一般管理費") {
-				plSummary.SGAndA.Previous = titleValue.Previous
-				plSummary.SGAndA.Current = titleValue.Current
+
+			if titleName == "営業外収益合計" && hasValue {
+				plSummary.NonOperatingIncome = titleValue
+				row.assign("non_operating_income", titleValue)
 			}
-			if strings.Contains(titleName, "売上高") {
-				plSummary.Sales.Previous = titleValue.Previous
-				plSummary.Sales.Current = titleValue.Current
-				// fundamental
-				fundamental.Sales = titleValue.Current
+			if titleName == "営業外費用合計" && hasValue {
+				plSummary.NonOperatingExpenses = titleValue
+				row.assign("non_operating_expenses", titleValue)
 			}
-			if strings.Contains(titleName, "営業利益") {
-				plSummary.OperatingProfit.Previous = titleValue.Previous
-				plSummary.OperatingProfit.Current = titleValue.Current
-				// fundamental
-				fundamental.OperatingProfit = titleValue.Current
+			if titleName == "特別利益合計" && hasValue {
+				plSummary.ExtraordinaryIncome = titleValue
+				row.assign("extraordinary_income", titleValue)
 			}
-			if titleName == "営業損失（△）" {
-				// fmt.Println("営業損失とだけ書いてあります")
-				if plSummary.OperatingProfit.Previous == 0 {
-					plSummary.OperatingProfit.Previous = titleValue.Previous
-				}
-				if plSummary.OperatingProfit.Current == 0 {
-					plSummary.OperatingProfit.Current = titleValue.Current
-				}
-				// fundamental
-				if fundamental.OperatingProfit == 0 {
-					fundamental.OperatingProfit = titleValue.Current
-				}
+			if titleName == "特別損失合計" && hasValue {
+				plSummary.ExtraordinaryLoss = titleValue
+				row.assign("extraordinary_loss", titleValue)
 			}
-			// 営業収益の後に営業収益合計がある場合は上書き
-			if (strings.Contains(titleName, "営業収益") || strings.Contains(titleName, "売上収益")) && !isOperatingRevenueDone {
-				plSummary.HasOperatingRevenue = true
-				plSummary.OperatingRevenue.Previous = titleValue.Previous
-				plSummary.OperatingRevenue.Current = titleValue.Current
-				// fundamental
-				fundamental.HasOperatingRevenue = true
-				fundamental.OperatingRevenue = titleValue.Current
-				if titleName == "営業収益合計" {
-					isOperatingRevenueDone = true
+			if (strings.HasPrefix(titleName, "税引前") || strings.HasPrefix(titleName, "税金等調整前")) && hasValue {
+				plSummary.ProfitBeforeTax = titleValue
+				row.assign("profit_before_tax", titleValue)
+			}
+			// 内訳がある場合は法人税等合計を使う
+			if titleName == "法人税等合計" && hasValue {
+				plSummary.IncomeTaxes = titleValue
+				row.assign("income_taxes", titleValue)
+			} else if (titleName == "法人税等" || titleName == "法人所得税費用") && hasValue && plSummary.IncomeTaxes == (TitleValue{}) {
+				plSummary.IncomeTaxes = titleValue
+				row.assign("income_taxes", titleValue)
+			}
+			if slices.Contains(NetIncomeTitles, titleName) && hasValue {
+				plSummary.NetIncome = titleValue
+				row.assign("net_income", titleValue)
+				// fundamental (親会社株主に帰属する当期純利益がない場合)
+				if fundamental.NetIncome == 0 {
+					fundamental.NetIncome = titleValue.Current
 				}
 			}
-			// 営業費用の後に営業費用合計がある場合は上書き
-			if strings.Contains(titleName, "営業費用") && !isOperatingCostDone {
-				plSummary.HasOperatingCost = true
-				plSummary.OperatingCost.Previous = titleValue.Previous
-				plSummary.OperatingCost.Current = titleValue.Current
+			// 親会社の所有者に帰属する当期包括利益 は除く
+			if (strings.HasPrefix(titleName, "親会社株主に帰属する当期純") || strings.HasPrefix(titleName, "親会社の所有者に帰属する当期")) && !strings.Contains(titleName, "包括利益") && hasValue {
+				plSummary.NetIncomeAttributableToOwners = titleValue
+				row.assign("net_income_attributable_to_owners", titleValue)
 				// fundamental
-				fundamental.HasOperatingCost = true
-				fundamental.OperatingCost = titleValue.Current
-				if titleName == "営業費用合計" {
-					isOperatingCostDone = true
-				}
+				fundamental.NetIncome = titleValue.Current
+			}
+			// 包括利益 (損益及び包括利益計算書・包括利益計算書)
+			if (titleName == "その他の包括利益合計" || titleName == "税引後その他の包括利益") && hasValue {
+				plSummary.OtherComprehensiveIncome = titleValue
+				row.assign("other_comprehensive_income", titleValue)
+			}
+			if slices.Contains(ComprehensiveIncomeTitles, titleName) && hasValue {
+				plSummary.ComprehensiveIncome = titleValue
+				row.assign("comprehensive_income", titleValue)
+			}
+			if (strings.HasPrefix(titleName, "親会社株主に係る包括利益") || strings.HasPrefix(titleName, "親会社の所有者に帰属する当期包括利益")) && hasValue {
+				plSummary.OwnersComprehensiveIncome = titleValue
+				row.assign("owners_comprehensive_income", titleValue)
 			}
 
 			if len(splitTdTexts) == 1 && titleTexts != nil && strings.Contains(titleTexts[0], "単位：") && plSummary.UnitString == "" {
@@ -1875,38 +2310,53 @@ func UpdateEverySummary(doc *goquery.Document, docID string, dateKey string, sum
 				splitUnitStrs := strings.Split(baseStr, "：")
 				if len(splitUnitStrs) >= 2 {
 					plSummary.UnitString = splitUnitStrs[1]
+					row.assignText("unit_string", plSummary.UnitString)
 				}
 			}
 		} else if summaryType == "cf" {
-			if strings.Contains(titleName, "営業活動による") {
-				// TODO: テスト後消す
-				// fmt.Println("==================")
-				// fmt.Println("項目名⭐️: ", titleName)
-				// fmt.Println("今期のテキスト⭐️: ", currentText)
-				cfSummary.OperatingCF.Previous = titleValue.Previous
-				cfSummary.OperatingCF.Current = titleValue.Current
+			if isLabelMatched {
+				switch labelKey {
+				case "operating_cf":
+					cfSummary.OperatingCF = titleValue
+				case "investing_cf":
+					cfSummary.InvestingCF = titleValue
+				case "financing_cf":
+					cfSummary.FinancingCF = titleValue
+				case "start_cash":
+					cfSummary.StartCash = titleValue
+				case "end_cash":
+					cfSummary.EndCash = titleValue
+				}
+				row.assign(labelKey, titleValue)
 			}
-			if strings.Contains(titleName, "投資活動による") {
-				cfSummary.InvestingCF.Previous = titleValue.Previous
-				cfSummary.InvestingCF.Current = titleValue.Current
+
+			if (titleName == "減価償却費" || titleName == "減価償却費及び償却費") && hasValue {
+				cfSummary.Depreciation = titleValue
+				row.assign("depreciation", titleValue)
+			}
+			if strings.HasPrefix(titleName, "有形固定資産の取得による支出") && hasValue {
+				cfSummary.Capex = titleValue
+				row.assign("capex", titleValue)
 			}
-			if strings.Contains(titleName, "財務活動による") {
-				cfSummary.FinancingCF.Previous = titleValue.Previous
-				cfSummary.FinancingCF.Current = titleValue.Current
+			if slices.Contains(DividendsPaidTitles, titleName) && hasValue {
+				cfSummary.DividendsPaid = titleValue
+				row.assign("dividends_paid", titleValue)
 			}
-			if strings.Contains(titleName, "期首残高") {
-				cfSummary.StartCash.Previous = titleValue.Previous
-				cfSummary.StartCash.Current = titleValue.Current
+			if strings.HasPrefix(titleName, "自己株式の取得による支出") && hasValue {
+				cfSummary.ShareBuybacks = titleValue
+				row.assign("share_buybacks", titleValue)
 			}
-			if strings.Contains(titleName, "期末残高") {
-				cfSummary.EndCash.Previous = titleValue.Previous
-				cfSummary.EndCash.Current = titleValue.Current
+			// 連結範囲の変動による増減額などは対象外
+			if (strings.HasPrefix(titleName, "現金及び現金同等物の増") || strings.HasPrefix(titleName, "現金及び現金同等物の減")) && hasValue {
+				cfSummary.NetChange = titleValue
+				row.assign("net_change", titleValue)
 			}
 
 			if len(splitTdTexts) == 1 && titleTexts != nil && strings.Contains(titleTexts[0], "単位：") && cfSummary.UnitString == "" {
 				formatUnitStr := FormatUnitStr(splitTdTexts[0])
 				if formatUnitStr != "" {
 					cfSummary.UnitString = formatUnitStr
+					row.assignText("unit_string", cfSummary.UnitString)
 				}
 			}
 		}
@@ -1937,29 +2387,29 @@ func PutOriginalHTMLToS3(docID string, dateKey string, fileKey string, body stri
 		// HTMLデータを加工
 		unescapedStr = FormatHtmlTable(unescapedStr)
 
-		// 同名ファイルの存在チェック
-		existsFile, err := CheckFileExists(S3Client, EDINETBucketName, HTMLFileKey)
-		if err != nil {
-			fmt.Println("存在チェック時のエラー❗️: ", err)
-		}
-		// fmt.Printf("%s は登録済みですか❓ %v\n", key, existsFile)
-
-		// 同名ファイルがなければ登録
-		if !existsFile {
-			_, err = S3Client.PutObject(context.TODO(), &s3.PutObjectInput{
-				Bucket:      aws.String(EDINETBucketName),
-				Key:         aws.String(HTMLFileKey),
-				Body:        strings.NewReader(string(unescapedStr)),
-				ContentType: aws.String("text/html"),
-			})
+		if DryRun == "true" {
+			err := PutDryRunObject(EDINETBucketName, HTMLFileKey, []byte(unescapedStr))
 			if err != nil {
-				ErrMsg = "S3 Original HTML PutObject error: "
+				ErrMsg = "Original HTML dry-run 書き出しエラー: "
 				RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
-				return
 			}
-			uploadDoneMsg := fmt.Sprintf("オリジナルHTML (%s) を登録しました ⭕️ ", HTMLFileKey)
-			fmt.Println(uploadDoneMsg)
+			return
 		}
+
+		// HTML の加工 (FormatHtmlTable) の変更を反映するため、登録済みの場合も上書きする
+		_, err := S3Client.PutObject(context.TODO(), &s3.PutObjectInput{
+			Bucket:      aws.String(EDINETBucketName),
+			Key:         aws.String(HTMLFileKey),
+			Body:        strings.NewReader(string(unescapedStr)),
+			ContentType: aws.String("text/html"),
+		})
+		if err != nil {
+			ErrMsg = "S3 Original HTML PutObject error: "
+			RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+			return
+		}
+		uploadDoneMsg := fmt.Sprintf("オリジナルHTML (%s) を登録しました ⭕️ ", HTMLFileKey)
+		fmt.Println(uploadDoneMsg)
 	}
 }
 
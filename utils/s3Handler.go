@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
-	"strconv"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
@@ -52,7 +51,7 @@ func CheckExistsS3Key(s3Client *s3.Client, bucketName string, key string) (bool,
 
 func CheckFileExists(s3Client *s3.Client, bucketName string, key string) (bool, error) {
 	_, err := s3Client.HeadObject(context.TODO(), &s3.HeadObjectInput{
-		Bucket: aws.String(BucketName),
+		Bucket: aws.String(bucketName),
 		Key:    aws.String(key),
 	})
 	if err != nil {
@@ -68,37 +67,20 @@ func CheckFileExists(s3Client *s3.Client, bucketName string, key string) (bool,
 	return true, nil
 }
 
+/*
+金額の文字列を int に変換する
+空欄の場合は ErrEmptyJPNumber、小数の場合は ErrDecimalJPNumber を返す
+－, ― などの記号は 0 を返す (記号と 0 を区別する場合は ParseJPNumber の IsDash を使う)
+*/
 func ConvertTextValue2IntValue(text string) (int, error) {
-	isMinus := false
-	// fmt.Println("==========================")
-	// fmt.Println("元text: ", text)
-
-	// , を削除
-	text = strings.ReplaceAll(text, ",", "")
-	// ※1 などを削除
-	asteriskAndHalfWidthNums := AsteriskAndHalfWidthNumRe.FindAllString(text, -1)
-	for _, asteriskAndHalfWidthNum := range asteriskAndHalfWidthNums {
-		text = strings.ReplaceAll(text, asteriskAndHalfWidthNum, "")
-	}
-	// マイナスチェック
-	if strings.Contains(text, "△") {
-		isMinus = true
-	}
-	// 数字部分のみ抜き出す
-	text = OnlyNumRe.FindString(text)
-	// スペースを削除
-	text = strings.TrimSpace(text)
-	// マイナスの場合、 - を先頭に追加する
-	if isMinus {
-		// previousMatch = "-" + previousMatch
-		text = "-" + text
-	}
-	intValue, err := strconv.Atoi(text)
+	number, err := ParseJPNumber(text)
 	if err != nil {
-		// fmt.Println("strconv.Atoi error text: ", text)
 		return 0, err
 	}
-	return intValue, nil
+	if number.IsNil {
+		return 0, ErrEmptyJPNumber
+	}
+	return number.Int()
 }
 
 func QueryByName(svc *dynamodb.Client, tableName string, companyName string, edinetCode string) ([]map[string]types.AttributeValue, error) {
@@ -126,6 +108,9 @@ func QueryByName(svc *dynamodb.Client, tableName string, companyName string, edi
 }
 
 func PutJSONObject(s3Client *s3.Client, bucketName string, key string, body []byte) error {
+	if DryRun == "true" {
+		return PutDryRunObject(bucketName, key, body)
+	}
 	_, err := s3Client.PutObject(context.TODO(), &s3.PutObjectInput{
 		Bucket:      aws.String(bucketName),
 		Key:         aws.String(key),
@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 財務諸表の範囲
+const (
+	ScopeConsolidated = "consolidated" // 連結
+	ScopeSolo         = "solo"         // 個別
+)
+
+/*
+財務諸表の名称から範囲を判定する
+*/
+func StatementScope(label string) string {
+	if strings.Contains(label, "連結") {
+		return ScopeConsolidated
+	}
+	return ScopeSolo
+}
+
+/*
+範囲を指定してパース対象の TextBlock を選ぶ
+連結は IFRS を優先し、対象がない場合は空文字を返す
+*/
+func SelectScopedStatementMatch(fileType string, scope string, matches StatementMatches) (string, string) {
+	switch fileType + "/" + scope {
+	case "BS/" + ScopeConsolidated:
+		if matches.ConsolidatedBSIFRS != "" {
+			return "連結財政状態計算書", matches.ConsolidatedBSIFRS
+		} else if matches.ConsolidatedBS != "" {
+			return "連結貸借対照表", matches.ConsolidatedBS
+		}
+	case "BS/" + ScopeSolo:
+		if matches.SoloBS != "" {
+			return "貸借対照表", matches.SoloBS
+		}
+	case "PL/" + ScopeConsolidated:
+		if matches.ConsolidatedPLIFRS != "" {
+			return "連結損益計算書（IFRS）", matches.ConsolidatedPLIFRS
+		} else if matches.ConsolidatedPLCIIFRS != "" {
+			return "連結純損益及びその他の包括利益計算書（IFRS）", matches.ConsolidatedPLCIIFRS
+		} else if matches.ConsolidatedPL != "" {
+			return "連結損益計算書", matches.ConsolidatedPL
+		} else if matches.ConsolidatedPLCI != "" {
+			return "連結損益及び包括利益計算書", matches.ConsolidatedPLCI
+		}
+	case "PL/" + ScopeSolo:
+		if matches.SoloPL != "" {
+			return "損益計算書", matches.SoloPL
+		}
+	case "CF/" + ScopeConsolidated:
+		if matches.ConsolidatedCFIFRS != "" {
+			return "連結キャッシュ・フロー計算書 (IFRS)", matches.ConsolidatedCFIFRS
+		} else if matches.ConsolidatedCF != "" {
+			return "連結キャッシュ・フロー計算書", matches.ConsolidatedCF
+		}
+	case "CF/" + ScopeSolo:
+		if matches.SoloCFIFRS != "" {
+			return "キャッシュ・フロー計算書 (IFRS)", matches.SoloCFIFRS
+		} else if matches.SoloCF != "" {
+			return "キャッシュ・フロー計算書", matches.SoloCF
+		}
+	case "SS/" + ScopeConsolidated:
+		if matches.ConsolidatedSSIFRS != "" {
+			return "連結持分変動計算書", matches.ConsolidatedSSIFRS
+		} else if matches.ConsolidatedSS != "" {
+			return "連結株主資本等変動計算書", matches.ConsolidatedSS
+		}
+	case "SS/" + ScopeSolo:
+		if matches.SoloSS != "" {
+			return "株主資本等変動計算書", matches.SoloSS
+		}
+	}
+	return "", ""
+}
+
+/*
+連結財務諸表を作成している提出者の個別財務諸表を {EDINETコード}/{BS, PL, CF}/solo/ 配下に登録する
+個別のサマリーはファンダメンタル・企業情報の登録には使わない
+@returns
+
+	時系列データに統合する個別のサマリー
+*/
+func RegisterSoloStatements(docID string, dateKey string, EDINETCode string, companyName string, periodStart string, periodEnd string, matches StatementMatches, industryTemplate IndustryTemplate, labelDictionary *LabelDictionary, objectKeys []string) TimeSeriesSource {
+	var solo TimeSeriesSource
+	for _, fileType := range []string{"BS", "PL", "CF"} {
+		primaryLabel, _ := SelectStatementMatch(fileType, matches)
+		if StatementScope(primaryLabel) == ScopeSolo {
+			// 個別のみの場合は登録済み
+			continue
+		}
+		_, match := SelectScopedStatementMatch(fileType, ScopeSolo, matches)
+		if match == "" {
+			continue
+		}
+
+		HTMLBody := []byte(matches.StatementHTML(match))
+		doc, err := goquery.NewDocumentFromReader(bytes.NewReader(HTMLBody))
+		if err != nil {
+			fmt.Printf("「%s」の個別%sの goquery.NewDocumentFromReader error: %v\n", companyName, fileType, err)
+			continue
+		}
+
+		var summary interface{}
+		isValid := true
+		// 個別のサマリーではファンダメンタルを更新しない
+		var soloFundamental Fundamental
+		switch fileType {
+		case "BS":
+			bsSummary := Summary{CompanyName: companyName, PeriodStart: periodStart, PeriodEnd: periodEnd, Scope: ScopeSolo}
+			UpdateEverySummary(doc, docID, dateKey, "bs", &bsSummary, nil, nil, &soloFundamental, labelDictionary, nil)
+			UpdateIndustryBSSummary(doc, industryTemplate, &bsSummary, &soloFundamental)
+			UpdateBSRatios(&bsSummary, nil)
+			summary = bsSummary
+			solo.Summary = bsSummary
+		case "PL":
+			plSummary := PLSummary{CompanyName: companyName, PeriodStart: periodStart, PeriodEnd: periodEnd, Scope: ScopeSolo}
+			UpdateEverySummary(doc, docID, dateKey, "pl", nil, &plSummary, nil, &soloFundamental, labelDictionary, nil)
+			UpdateIndustryPLSummary(doc, industryTemplate, &plSummary, &soloFundamental)
+			isValid = ValidateIndustryPLSummary(plSummary, industryTemplate)
+			summary = plSummary
+			solo.PLSummary, solo.IsPLSummaryValid = plSummary, isValid
+		case "CF":
+			cfSummary := CFSummary{CompanyName: companyName, PeriodStart: periodStart, PeriodEnd: periodEnd, Scope: ScopeSolo}
+			UpdateEverySummary(doc, docID, dateKey, "cf", nil, nil, &cfSummary, nil, labelDictionary, nil)
+			CompleteCFSummary(&cfSummary)
+			isValid = ValidateCFSummary(cfSummary)
+			summary = cfSummary
+			solo.CFSummary, solo.IsCFSummaryValid = cfSummary, isValid
+		}
+
+		fileNamePattern := fmt.Sprintf("%s-%s-%s-from-%s-to-%s", EDINETCode, docID, fileType, periodStart, periodEnd)
+		var soloWg sync.WaitGroup
+		if Parallel == "true" {
+			soloWg.Add(1)
+		}
+		PutScopedFileToS3(docID, dateKey, EDINETCode, companyName, ScopeSolo, fileNamePattern, "html", HTMLBody, objectKeys, &soloWg)
+		if !isValid {
+			PrintValidatedSummaryMsg(companyName, "個別"+fileType, summary, isValid)
+			continue
+		}
+		if Parallel == "true" {
+			soloWg.Add(1)
+		}
+		HandleRegisterScopedJSON(docID, dateKey, EDINETCode, companyName, ScopeSolo, fileNamePattern, summary, objectKeys, &soloWg)
+	}
+	return solo
+}
@@ -0,0 +1,202 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// セグメントの売上高に使う要素 (優先順)
+var SegmentSalesElements = []string{
+	"jpcrp_cor:RevenuesFromExternalCustomers",
+	"jppfs_cor:NetSales",
+	"jppfs_cor:OperatingRevenue1",
+	"jppfs_cor:OperatingRevenue2",
+	"jpigp_cor:RevenueFromExternalCustomersIFRS",
+	"jpigp_cor:RevenueIFRS",
+	"jpigp_cor:NetSalesIFRS",
+}
+
+// セグメント利益に使う要素 (優先順)
+var SegmentProfitElements = []string{
+	"jpcrp_cor:SegmentProfitLoss",
+	"jppfs_cor:OperatingIncome",
+	"jppfs_cor:OrdinaryIncome",
+	"jpigp_cor:SegmentProfitLossIFRS",
+	"jpigp_cor:OperatingProfitLossIFRS",
+	"jpigp_cor:ProfitLossBeforeTaxIFRS",
+}
+
+// 提出者別タクソノミにない (jpcrp_cor の) メンバーのラベル
+var SegmentMemberLabels = map[string]string{
+	"ReportableSegmentsMember":                 "報告セグメント合計",
+	"OtherSegmentsMember":                      "その他",
+	"TotalOfReportableSegmentsAndOthersMember": "合計",
+	"ReconcilingItemsMember":                   "調整額",
+	"UnallocatedAmountsAndEliminationMember":   "全社・消去",
+	"JapanMember":                              "日本",
+	"NorthAmericaMember":                       "北米",
+	"EuropeMember":                             "欧州",
+	"AsiaMember":                               "アジア",
+	"OtherAreasMember":                         "その他の地域",
+	"UnitedStatesMember":                       "米国",
+	"ChinaMember":                              "中国",
+}
+
+/*
+ディメンションの軸からセグメントの種類を判定する
+@returns
+
+	business (事業別), geography (地域別) のいずれか (該当しない場合は空文字)
+*/
+func ClassifySegmentAxis(dimension string) string {
+	local := dimension
+	if i := strings.LastIndex(dimension, ":"); i >= 0 {
+		local = dimension[i+1:]
+	}
+	switch {
+	case strings.Contains(local, "Geographic") || strings.Contains(local, "Area") || strings.Contains(local, "Region"):
+		return "geography"
+	case strings.Contains(local, "Segment"):
+		return "business"
+	}
+	return ""
+}
+
+/*
+XBRL のディメンション付きコンテキストからセグメントごとの売上高・利益を取得する
+連結・個別の軸などが重なったコンテキスト (メンバーが 2 つ以上) は対象外とする
+@params
+
+	labels: 名称リンクベースの要素 ID → ラベル
+*/
+func CreateSegmentValues(contexts []Context, facts []Fact, labels map[string]string) []SegmentValue {
+	// コンテキスト ID → セグメントの位置
+	contextIndexes := make(map[string]int)
+	// コンテキスト ID → 当期 (true) か前期 (false) か
+	contextIsCurrent := make(map[string]bool)
+	// 軸 + メンバー → セグメントの位置
+	segmentIndexes := make(map[string]int)
+	var segments []SegmentValue
+
+	for _, context := range contexts {
+		isCurrent := strings.HasPrefix(context.ID, "CurrentYearDuration_")
+		isPrevious := strings.HasPrefix(context.ID, "Prior1YearDuration_")
+		if (!isCurrent && !isPrevious) || len(context.Entity.Segment.ExplicitMembers) != 1 {
+			continue
+		}
+		member := context.Entity.Segment.ExplicitMembers[0]
+		axis := ClassifySegmentAxis(member.Dimension)
+		if axis == "" {
+			continue
+		}
+		memberName := strings.TrimSpace(member.Value)
+		segmentKey := axis + "@" + memberName
+		index, ok := segmentIndexes[segmentKey]
+		if !ok {
+			index = len(segments)
+			segmentIndexes[segmentKey] = index
+			segments = append(segments, SegmentValue{
+				Axis:   axis,
+				Member: memberName,
+				Label:  SegmentLabel(memberName, labels),
+			})
+		}
+		contextIndexes[context.ID] = index
+		contextIsCurrent[context.ID] = isCurrent
+	}
+	if len(segments) == 0 {
+		return nil
+	}
+
+	// 要素ごとに優先順位の高いものから値を設定する
+	salesFound := make(map[string]int)
+	profitFound := make(map[string]int)
+	for _, fact := range facts {
+		index, ok := contextIndexes[fact.ContextRef]
+		if !ok || fact.IsNil {
+			continue
+		}
+		value, err := strconv.Atoi(fact.Value)
+		if err != nil {
+			continue
+		}
+		if priority := elementPriority(SegmentSalesElements, fact.Name); priority >= 0 {
+			setSegmentValue(&segments[index].Sales, contextIsCurrent[fact.ContextRef], value, priority, fact.ContextRef, salesFound)
+		}
+		if priority := elementPriority(SegmentProfitElements, fact.Name); priority >= 0 {
+			setSegmentValue(&segments[index].Profit, contextIsCurrent[fact.ContextRef], value, priority, fact.ContextRef, profitFound)
+		}
+	}
+
+	// 売上高・利益ともに取得できなかったセグメントは除く
+	var results []SegmentValue
+	for _, segment := range segments {
+		if segment.Sales == (TitleValue{}) && segment.Profit == (TitleValue{}) {
+			continue
+		}
+		results = append(results, segment)
+	}
+	return results
+}
+
+// 要素の優先順位 (対象外の場合は -1)
+func elementPriority(elements []string, name string) int {
+	for i, element := range elements {
+		if element == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// 同じコンテキストで優先順位の高い要素の値が設定済みの場合は上書きしない
+func setSegmentValue(titleValue *TitleValue, isCurrent bool, value int, priority int, contextRef string, found map[string]int) {
+	if foundPriority, ok := found[contextRef]; ok && foundPriority <= priority {
+		return
+	}
+	found[contextRef] = priority
+	if isCurrent {
+		titleValue.Current = value
+	} else {
+		titleValue.Previous = value
+	}
+}
+
+/*
+メンバーのラベルを取得する
+名称リンクベース、jpcrp_cor のメンバー、要素名の順に探す
+*/
+func SegmentLabel(memberName string, labels map[string]string) string {
+	if label, ok := labels[LabelElementID(memberName)]; ok {
+		return label
+	}
+	local := memberName
+	if i := strings.LastIndex(memberName, ":"); i >= 0 {
+		local = memberName[i+1:]
+	}
+	if label, ok := SegmentMemberLabels[local]; ok {
+		return label
+	}
+	local = strings.TrimSuffix(local, "Member")
+	local = strings.TrimSuffix(local, "ReportableSegments")
+	local = strings.TrimSuffix(local, "ReportableSegment")
+	return local
+}
+
+/*
+セグメント情報を作成する
+セグメントが見つからない場合は false を返す
+*/
+func CreateSegmentSummary(companyName string, periodStart string, periodEnd string, contexts []Context, facts []Fact, labels map[string]string) (SegmentSummary, bool) {
+	segments := CreateSegmentValues(contexts, facts, labels)
+	if len(segments) == 0 {
+		return SegmentSummary{}, false
+	}
+	return SegmentSummary{
+		CompanyName: companyName,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		UnitString:  "円",
+		Segments:    segments,
+	}, true
+}
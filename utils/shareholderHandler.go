@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 大株主の状況の最大件数 (No1MajorShareholdersMember ~ No10MajorShareholdersMember)
+const MaxMajorShareholders = 10
+
+// 発行済株式総数に使う要素 (優先順)
+var IssuedSharesElements = []string{
+	"jpcrp_cor:TotalNumberOfIssuedSharesSummaryOfBusinessResults",
+	"jpcrp_cor:NumberOfIssuedSharesAsOfFiscalYearEndIssuedSharesTotalNumberOfSharesEtc",
+}
+
+// 1株当たり配当額に使う要素 (優先順)
+var DividendPerShareElements = []string{
+	"jpcrp_cor:DividendPaidPerShareSummaryOfBusinessResults",
+}
+
+// 配当性向に使う要素 (優先順)
+var PayoutRatioElements = []string{
+	"jpcrp_cor:PayoutRatioSummaryOfBusinessResults",
+}
+
+/*
+大株主の状況、発行済株式総数、自己株式数、配当の情報を取得する
+大株主は XBRL の事実 (MajorShareholdersAxis) から取得し、見つからない場合は TextBlock の表から取得する
+@returns
+
+	大株主・株式数・配当のいずれも見つからない場合は false
+*/
+func CreateShareholderSummary(companyName string, periodStart string, periodEnd string, facts []Fact) (ShareholderSummary, bool) {
+	summary := ShareholderSummary{
+		CompanyName: companyName,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+	}
+
+	summary.MajorShareholders = FindMajorShareholders(facts)
+	if len(summary.MajorShareholders) == 0 {
+		textBlock := FindFactValue(facts, "jpcrp_cor:MajorShareholdersTextBlock", "FilingDateInstant", "CurrentYearInstant")
+		summary.MajorShareholders = ParseMajorShareholdersTable(textBlock)
+	}
+	for _, shareholder := range summary.MajorShareholders {
+		summary.TopShareholderRatio += shareholder.Ratio
+	}
+	summary.TopShareholderRatio = roundRatio(summary.TopShareholderRatio)
+
+	for _, element := range IssuedSharesElements {
+		issuedShares := FindFactValue(facts, element, "CurrentYearInstant_NonConsolidatedMember", "CurrentYearInstant", "FilingDateInstant")
+		if issuedShares != "" {
+			summary.IssuedShares, _ = strconv.Atoi(issuedShares)
+			break
+		}
+	}
+	treasuryShares := FindFactValue(facts, "jpcrp_cor:TotalNumberOfSharesHeldTreasurySharesEtc", "CurrentYearInstant", "FilingDateInstant")
+	summary.TreasuryShares, _ = strconv.Atoi(treasuryShares)
+
+	// 提出会社の値 (連結財務諸表を作成していない場合はメンバーなし) を使う
+	summary.DividendPerShare = FindFloatTitleValue(facts, DividendPerShareElements, false)
+	if summary.DividendPerShare == (FloatTitleValue{}) {
+		summary.DividendPerShare = FindFloatTitleValue(facts, DividendPerShareElements, true)
+	}
+	payoutRatio := FindFloatTitleValue(facts, PayoutRatioElements, false)
+	if payoutRatio == (FloatTitleValue{}) {
+		payoutRatio = FindFloatTitleValue(facts, PayoutRatioElements, true)
+	}
+	summary.PayoutRatio = FloatTitleValue{
+		Previous: roundRatio(payoutRatio.Previous * 100),
+		Current:  roundRatio(payoutRatio.Current * 100),
+	}
+
+	isEmpty := len(summary.MajorShareholders) == 0 && summary.IssuedShares == 0 && summary.TreasuryShares == 0 &&
+		summary.DividendPerShare == (FloatTitleValue{}) && summary.PayoutRatio == (FloatTitleValue{})
+	return summary, !isEmpty
+}
+
+/*
+XBRL の事実から大株主を取得する
+コンテキストは CurrentYearInstant_No1MajorShareholdersMember の形式
+*/
+func FindMajorShareholders(facts []Fact) []MajorShareholder {
+	var shareholders []MajorShareholder
+	for rank := 1; rank <= MaxMajorShareholders; rank++ {
+		contextRef := fmt.Sprintf("CurrentYearInstant_No%dMajorShareholdersMember", rank)
+		name := FindFactValue(facts, "jpcrp_cor:NameMajorShareholders", contextRef)
+		if name == "" {
+			continue
+		}
+		shareholder := MajorShareholder{
+			Rank:    rank,
+			Name:    strings.Join(strings.Fields(name), " "),
+			Address: strings.Join(strings.Fields(FindFactValue(facts, "jpcrp_cor:AddressMajorShareholders", contextRef)), " "),
+		}
+		shareholder.Shares, _ = strconv.Atoi(FindFactValue(facts, "jpcrp_cor:NumberOfSharesHeld", contextRef))
+		ratio, _ := strconv.ParseFloat(FindFactValue(facts, "jpcrp_cor:ShareholdingRatio", contextRef), 64)
+		shareholder.Ratio = roundRatio(ratio * 100)
+		shareholders = append(shareholders, shareholder)
+	}
+	return shareholders
+}
+
+/*
+大株主の状況の TextBlock の表から大株主を取得する
+各行の最後の 2 列を所有株式数、所有割合 (%) とし、「計」の行は除く
+*/
+func ParseMajorShareholdersTable(textBlock string) []MajorShareholder {
+	if textBlock == "" {
+		return nil
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(textBlock))
+	if err != nil {
+		fmt.Println("大株主の状況の goquery.NewDocumentFromReader error: ", err)
+		return nil
+	}
+
+	// 所有株式数の単位
+	multiplier := 1.0
+	tableText := doc.Text()
+	if strings.Contains(tableText, "千株") {
+		multiplier = 1000
+	} else if strings.Contains(tableText, "百株") {
+		multiplier = 100
+	}
+
+	var shareholders []MajorShareholder
+	doc.Find("tr").Each(func(i int, tr *goquery.Selection) {
+		var cells []string
+		tr.Find("td").Each(func(j int, td *goquery.Selection) {
+			cells = append(cells, strings.Join(strings.Fields(td.Text()), " "))
+		})
+		if len(cells) < 3 || cells[0] == "" || strings.HasSuffix(strings.ReplaceAll(cells[0], " ", ""), "計") {
+			return
+		}
+		ratio, err := strconv.ParseFloat(strings.NewReplacer(",", "", "%", "", " ", "").Replace(cells[len(cells)-1]), 64)
+		if err != nil {
+			return
+		}
+		shares, err := strconv.ParseFloat(strings.NewReplacer(",", "", " ", "").Replace(cells[len(cells)-2]), 64)
+		if err != nil {
+			return
+		}
+		shareholder := MajorShareholder{
+			Rank:   len(shareholders) + 1,
+			Name:   cells[0],
+			Shares: int(math.Round(shares * multiplier)),
+			Ratio:  ratio,
+		}
+		if len(cells) >= 4 {
+			shareholder.Address = cells[1]
+		}
+		shareholders = append(shareholders, shareholder)
+	})
+	return shareholders
+}
+
+// 割合を小数第 2 位までに丸める
+func roundRatio(ratio float64) float64 {
+	return math.Round(ratio*100) / 100
+}
@@ -0,0 +1,232 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+/*
+株主資本等変動計算書 (SS) を連結・個別ごとに {EDINETコード}/SS/{scope}/ 配下に登録する
+HTML と、配当・自己株式の取得・包括利益などをまとめた JSON を登録する
+*/
+func RegisterSSStatements(docID string, dateKey string, EDINETCode string, companyName string, periodStart string, periodEnd string, matches StatementMatches, objectKeys []string) {
+	for _, scope := range []string{ScopeConsolidated, ScopeSolo} {
+		_, match := SelectScopedStatementMatch("SS", scope, matches)
+		if match == "" {
+			continue
+		}
+
+		HTMLBody := []byte(matches.StatementHTML(match))
+		doc, err := goquery.NewDocumentFromReader(bytes.NewReader(HTMLBody))
+		if err != nil {
+			ErrMsg = "SS goquery.NewDocumentFromReader error: "
+			RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+			continue
+		}
+
+		ssSummary := SSSummary{
+			CompanyName: companyName,
+			PeriodStart: periodStart,
+			PeriodEnd:   periodEnd,
+			Scope:       scope,
+		}
+		UpdateSSSummary(doc, &ssSummary)
+
+		ssFileNamePattern := fmt.Sprintf("%s-%s-SS-from-%s-to-%s", EDINETCode, docID, periodStart, periodEnd)
+		var ssWg sync.WaitGroup
+		if Parallel == "true" {
+			ssWg.Add(1)
+		}
+		// HTML はバリデーションの結果に関わらず送信
+		PutScopedFileToS3(docID, dateKey, EDINETCode, companyName, scope, ssFileNamePattern, "html", HTMLBody, objectKeys, &ssWg)
+
+		invalidSummaryType := "SS-" + scope
+		if !ValidateSSSummary(ssSummary) {
+			RegisterInvalidSummaryJson(docID, dateKey, invalidSummaryType, companyName)
+			PrintValidatedSummaryMsg(companyName, ssFileNamePattern, ssSummary, false)
+			continue
+		}
+		if Parallel == "true" {
+			ssWg.Add(1)
+		}
+		HandleRegisterScopedJSON(docID, dateKey, EDINETCode, companyName, scope, ssFileNamePattern, ssSummary, objectKeys, &ssWg)
+		deleteInvalidSummaryJsonItem(docID, dateKey, invalidSummaryType, companyName)
+	}
+}
+
+/*
+株主資本等変動計算書のサマリーのバリデーション
+当期末残高 (純資産合計) が取得できない場合は無効とする
+*/
+func ValidateSSSummary(ssSummary SSSummary) bool {
+	return ssSummary.CompanyName != "" &&
+		ssSummary.PeriodStart != "" &&
+		ssSummary.PeriodEnd != "" &&
+		ssSummary.EquityEnd.Current != 0
+}
+
+// 株主資本等変動計算書の前期・当期の見出し (「当期首残高」などの行と区別するため「前期」「当期」のみの見出しは使わない)
+var (
+	SSPreviousPeriodLabels = []string{"前連結会計年度", "前事業年度", "前中間連結会計期間", "前中間会計期間", "前第"}
+	SSCurrentPeriodLabels  = []string{"当連結会計年度", "当事業年度", "当中間連結会計期間", "当中間会計期間", "当第"}
+)
+
+/*
+株主資本等変動計算書の HTML からサマリーを作成する
+表の前の見出し (前連結会計年度・当連結会計年度など) で前期・当期の表を分け、それぞれ最後の表 (純資産合計の列がある表) の各行の最も右の数値を使う
+見出しがない場合は表の前半を前期、後半を当期とし、1 期分の表しかない場合は当期とする
+*/
+func UpdateSSSummary(doc *goquery.Document, ssSummary *SSSummary) {
+	if ssSummary.UnitString == "" {
+		doc.Find("td, p").EachWithBreak(func(i int, s *goquery.Selection) bool {
+			text := strings.TrimSpace(s.Text())
+			if strings.Contains(text, "単位：") {
+				ssSummary.UnitString = FormatUnitStr(text)
+				return false
+			}
+			return true
+		})
+	}
+
+	previousTables, currentTables, hasHeadings := splitSSTables(doc)
+	if hasHeadings {
+		if len(currentTables) > 0 {
+			updateSSPeriod(currentTables[len(currentTables)-1], ssSummary, true)
+		}
+		if len(previousTables) > 0 {
+			updateSSPeriod(previousTables[len(previousTables)-1], ssSummary, false)
+		}
+		return
+	}
+
+	// 見出しがない場合
+	tables := append(previousTables, currentTables...)
+	if len(tables) == 0 {
+		return
+	}
+	currentTable := tables[len(tables)-1]
+	updateSSPeriod(currentTable, ssSummary, true)
+	if len(tables) >= 2 {
+		previousTable := tables[len(tables)/2-1]
+		updateSSPeriod(previousTable, ssSummary, false)
+	}
+}
+
+/*
+当期末残高の行がある表を、直前の見出し (表の外の文、もしくは表の 1 行目) で前期・当期に分ける
+@returns
+
+	見出しが 1 つもない場合は false (すべての表を当期の表として返す)
+*/
+func splitSSTables(doc *goquery.Document) ([]*goquery.Selection, []*goquery.Selection, bool) {
+	var previousTables, currentTables []*goquery.Selection
+	isPrevious, hasHeadings := false, false
+	doc.Find("*").Each(func(i int, s *goquery.Selection) {
+		if goquery.NodeName(s) == "table" {
+			if s.ParentsFiltered("table").Length() > 0 || !strings.Contains(s.Text(), "当期末残高") {
+				return
+			}
+			// 表の 1 行目に見出しがある場合
+			if period, ok := ssPeriodHeading(s.Find("tr").First().Text()); ok {
+				isPrevious, hasHeadings = period, true
+			}
+			if isPrevious {
+				previousTables = append(previousTables, s)
+			} else {
+				currentTables = append(currentTables, s)
+			}
+			return
+		}
+		// 表の外の文字列のみの要素
+		if s.Children().Length() > 0 || s.ParentsFiltered("table").Length() > 0 {
+			return
+		}
+		if period, ok := ssPeriodHeading(s.Text()); ok {
+			isPrevious, hasHeadings = period, true
+		}
+	})
+	return previousTables, currentTables, hasHeadings
+}
+
+// 見出しが前期のものであれば true, 当期のものであれば false を返す (見出しでない場合は ok が false)
+func ssPeriodHeading(text string) (bool, bool) {
+	text = removeSpaces(jpNumberReplacer.Replace(text))
+	text = strings.TrimLeft(text, "【[")
+	switch {
+	case hasAnyPrefix(text, SSPreviousPeriodLabels):
+		return true, true
+	case hasAnyPrefix(text, SSCurrentPeriodLabels):
+		return false, true
+	}
+	return false, false
+}
+
+// 表の各行の最も右の数値を前期もしくは当期の値として設定する
+func updateSSPeriod(table *goquery.Selection, ssSummary *SSSummary, isCurrent bool) {
+	table.Find("tr").Each(func(i int, tr *goquery.Selection) {
+		var cells []string
+		tr.Find("td").Each(func(j int, td *goquery.Selection) {
+			cells = append(cells, strings.Join(strings.Fields(td.Text()), ""))
+		})
+		if len(cells) < 2 || cells[0] == "" {
+			return
+		}
+		titleName := cells[0]
+
+		value, ok := lastIntValue(cells[1:])
+		if !ok {
+			return
+		}
+
+		var titleValue *TitleValue
+		switch {
+		case strings.HasPrefix(titleName, "当期首残高") && !strings.Contains(titleName, "変更"):
+			// 会計方針の変更を反映した残高は「会計方針の変更を反映した当期首残高」の行で上書きする
+			titleValue = &ssSummary.EquityBeginning
+		case strings.HasPrefix(titleName, "会計方針の変更を反映した当期首残高") || strings.HasPrefix(titleName, "遡及処理後当期首残高"):
+			titleValue = &ssSummary.EquityBeginning
+		case titleName == "剰余金の配当" || titleName == "配当金":
+			titleValue = &ssSummary.Dividends
+		case strings.HasPrefix(titleName, "自己株式の取得"):
+			titleValue = &ssSummary.ShareBuybacks
+		case strings.HasPrefix(titleName, "親会社株主に帰属する当期純利益") || strings.HasPrefix(titleName, "親会社株主に帰属する当期純損失") ||
+			titleName == "当期純利益" || titleName == "当期純損失" || titleName == "当期純利益又は当期純損失（△）" ||
+			titleName == "当期利益" || titleName == "当期損失":
+			titleValue = &ssSummary.NetIncome
+		case titleName == "その他の包括利益":
+			titleValue = &ssSummary.OtherComprehensiveIncome
+		case strings.HasPrefix(titleName, "当期包括利益") || titleName == "包括利益合計":
+			titleValue = &ssSummary.ComprehensiveIncome
+		case strings.HasPrefix(titleName, "株主資本以外の項目の当期変動額"):
+			titleValue = &ssSummary.NonShareholdersEquity
+		case strings.HasPrefix(titleName, "当期末残高"):
+			titleValue = &ssSummary.EquityEnd
+		default:
+			return
+		}
+		if isCurrent {
+			titleValue.Current = value
+		} else {
+			titleValue.Previous = value
+		}
+	})
+}
+
+// 最も右の数値を取得する (「―」などの記号と空欄は飛ばす)
+func lastIntValue(cells []string) (int, bool) {
+	for i := len(cells) - 1; i >= 0; i-- {
+		number, err := ParseJPNumber(cells[i])
+		if err != nil || number.IsNil || number.IsDash {
+			continue
+		}
+		value, err := number.Int()
+		if err == nil {
+			return value, true
+		}
+	}
+	return 0, false
+}
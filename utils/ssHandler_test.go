@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 株主資本等変動計算書の表 (最も右の列の値のみ指定する)
+func ssTestTable(beginning string, dividends string, end string) string {
+	return `<table>
+<tr><td><p>単位：百万円</p></td></tr>
+<tr><td><p>当期首残高</p></td><td><p>1</p></td><td><p>` + beginning + `</p></td></tr>
+<tr><td><p>剰余金の配当</p></td><td><p>1</p></td><td><p>` + dividends + `</p></td></tr>
+<tr><td><p>当期末残高</p></td><td><p>1</p></td><td><p>` + end + `</p></td></tr>
+</table>`
+}
+
+func TestUpdateSSSummary(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want SSSummary
+	}{
+		{
+			name: "前期・当期が 1 つずつの表",
+			html: `<p>前連結会計年度(自 2022年4月1日 至 2023年3月31日)</p>` + ssTestTable("900", "△10", "1,000") +
+				`<p>当連結会計年度(自 2023年4月1日 至 2024年3月31日)</p>` + ssTestTable("1,000", "△20", "1,200"),
+			want: SSSummary{
+				EquityBeginning: TitleValue{Previous: 900, Current: 1000},
+				Dividends:       TitleValue{Previous: -10, Current: -20},
+				EquityEnd:       TitleValue{Previous: 1000, Current: 1200},
+			},
+		},
+		{
+			name: "前期の表のみ 2 つに分かれている",
+			html: `<p>前連結会計年度(自 2022年4月1日 至 2023年3月31日)</p>` + ssTestTable("500", "△5", "600") + ssTestTable("900", "△10", "1,000") +
+				`<p>当連結会計年度(自 2023年4月1日 至 2024年3月31日)</p>` + ssTestTable("1,000", "△20", "1,200"),
+			want: SSSummary{
+				EquityBeginning: TitleValue{Previous: 900, Current: 1000},
+				Dividends:       TitleValue{Previous: -10, Current: -20},
+				EquityEnd:       TitleValue{Previous: 1000, Current: 1200},
+			},
+		},
+		{
+			name: "前期・当期とも 2 つに分かれている (見出しは全角スペース区切り)",
+			html: `<h4>前事業年度　(自　2022年４月１日　至　2023年３月31日)</h4>` + ssTestTable("500", "△5", "600") + ssTestTable("900", "△10", "1,000") +
+				`<h4>当事業年度　(自　2023年４月１日　至　2024年３月31日)</h4>` + ssTestTable("550", "△8", "700") + ssTestTable("1,000", "△20", "1,200"),
+			want: SSSummary{
+				EquityBeginning: TitleValue{Previous: 900, Current: 1000},
+				Dividends:       TitleValue{Previous: -10, Current: -20},
+				EquityEnd:       TitleValue{Previous: 1000, Current: 1200},
+			},
+		},
+		{
+			name: "見出しがない場合は前半を前期とする",
+			html: ssTestTable("900", "△10", "1,000") + ssTestTable("1,000", "△20", "1,200"),
+			want: SSSummary{
+				EquityBeginning: TitleValue{Previous: 900, Current: 1000},
+				Dividends:       TitleValue{Previous: -10, Current: -20},
+				EquityEnd:       TitleValue{Previous: 1000, Current: 1200},
+			},
+		},
+		{
+			name: "当期の表のみ",
+			html: `<p>当連結会計年度(自 2023年4月1日 至 2024年3月31日)</p>` + ssTestTable("1,000", "△20", "1,200"),
+			want: SSSummary{
+				EquityBeginning: TitleValue{Current: 1000},
+				Dividends:       TitleValue{Current: -20},
+				EquityEnd:       TitleValue{Current: 1200},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got SSSummary
+			UpdateSSSummary(doc, &got)
+			if got.EquityBeginning != tt.want.EquityBeginning || got.Dividends != tt.want.Dividends || got.EquityEnd != tt.want.EquityEnd {
+				t.Errorf("UpdateSSSummary() = %+v, want %+v", got, tt.want)
+			}
+			if got.UnitString != "百万円" {
+				t.Errorf("UnitString = %q, want 百万円", got.UnitString)
+			}
+		})
+	}
+}
+
+func TestValidateSSSummary(t *testing.T) {
+	base := SSSummary{CompanyName: "テスト株式会社", PeriodStart: "2023-04-01", PeriodEnd: "2024-03-31"}
+	if ValidateSSSummary(base) {
+		t.Error("すべての項目が 0 の場合は無効")
+	}
+	base.EquityEnd.Current = 1200
+	if !ValidateSSSummary(base) {
+		t.Error("当期末残高がある場合は有効")
+	}
+}
+
+func TestLastIntValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		cells  []string
+		want   int
+		wantOK bool
+	}{
+		{"最も右の数値", []string{"100", "△20"}, -20, true},
+		{"― は飛ばす", []string{"100", "―"}, 100, true},
+		{"空欄は飛ばす", []string{"100", ""}, 100, true},
+		{"小数は飛ばす", []string{"100", "12.5"}, 100, true},
+		{"数値がない", []string{"―", "－"}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := lastIntValue(tt.cells)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("lastIntValue(%q) = (%d, %v), want (%d, %v)", tt.cells, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
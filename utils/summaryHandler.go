@@ -0,0 +1,196 @@
+package utils
+
+import (
+	"strconv"
+)
+
+// 棚卸資産の内訳として表示される項目
+var InventoryTitles = []string{
+	"商品及び製品",
+	"仕掛品",
+	"原材料及び貯蔵品",
+	"商品",
+	"製品",
+	"原材料",
+	"貯蔵品",
+}
+
+// 長期借入金として合計する項目
+var LongTermBorrowingsTitles = []string{
+	"長期借入金",
+	"1年内返済予定の長期借入金",
+	"１年内返済予定の長期借入金",
+}
+
+// 社債として合計する項目
+var BondsTitles = []string{
+	"社債",
+	"1年内償還予定の社債",
+	"１年内償還予定の社債",
+}
+
+/*
+前期・当期それぞれの値を足す
+*/
+func AddTitleValue(a TitleValue, b TitleValue) TitleValue {
+	return TitleValue{
+		Previous: a.Previous + b.Previous,
+		Current:  a.Current + b.Current,
+	}
+}
+
+/*
+前期・当期それぞれの値を引く
+*/
+func SubTitleValue(a TitleValue, b TitleValue) TitleValue {
+	return TitleValue{
+		Previous: a.Previous - b.Previous,
+		Current:  a.Current - b.Current,
+	}
+}
+
+/*
+自己資本 (純資産 - 新株予約権 - 非支配株主持分)
+IFRS で親会社の所有者に帰属する持分合計がある場合はその値を使う
+*/
+func (s Summary) Equity() TitleValue {
+	if s.OwnersEquity != (TitleValue{}) {
+		return s.OwnersEquity
+	}
+	equity := SubTitleValue(s.NetAssets, s.SubscriptionRights)
+	return SubTitleValue(equity, s.NonControllingInterests)
+}
+
+/*
+前期・当期それぞれ自己資本を計算できるかどうか (純資産・親会社の所有者に帰属する持分合計のどちらかがある)
+*/
+func (s Summary) hasEquity() (bool, bool) {
+	return s.NetAssets.Previous != 0 || s.OwnersEquity.Previous != 0,
+		s.NetAssets.Current != 0 || s.OwnersEquity.Current != 0
+}
+
+/*
+有利子負債 (短期借入金 + 長期借入金 + 社債 + 社債及び借入金)
+*/
+func (s Summary) InterestBearingDebt() TitleValue {
+	debt := AddTitleValue(s.ShortTermBorrowings, s.LongTermBorrowings)
+	debt = AddTitleValue(debt, s.Bonds)
+	return AddTitleValue(debt, s.BondsAndBorrowings)
+}
+
+/*
+貸借対照表の値から財務指標 (ネットキャッシュ・自己資本比率・D/E レシオ) を計算してサマリーに設定する
+ファンダメンタルズには当期の自己資本比率・D/E レシオを設定する
+純資産が取得できなかった期の自己資本比率・D/E レシオは計算しない (0 とする)
+*/
+func UpdateBSRatios(summary *Summary, fundamental *Fundamental) {
+	equity := summary.Equity()
+	debt := summary.InterestBearingDebt()
+	// ネットキャッシュ (現金及び預金 - 有利子負債)
+	summary.NetCash = SubTitleValue(summary.CashAndDeposits, debt)
+	summary.EquityRatio = FloatTitleValue{}
+	summary.DERatio = FloatTitleValue{}
+	hasPreviousEquity, hasCurrentEquity := summary.hasEquity()
+	if hasPreviousEquity {
+		// 自己資本比率 (%)
+		summary.EquityRatio.Previous = equityRatio(equity.Previous, summary.TotalAssets.Previous)
+		// D/E レシオ (有利子負債 / 自己資本)
+		summary.DERatio.Previous = deRatio(debt.Previous, equity.Previous)
+	}
+	if hasCurrentEquity {
+		summary.EquityRatio.Current = equityRatio(equity.Current, summary.TotalAssets.Current)
+		summary.DERatio.Current = deRatio(debt.Current, equity.Current)
+	}
+	if fundamental != nil {
+		fundamental.EquityRatio = summary.EquityRatio.Current
+		fundamental.DERatio = summary.DERatio.Current
+	}
+}
+
+// 資産合計がない場合は 0 を返す
+func equityRatio(equity int, totalAssets int) float64 {
+	if totalAssets == 0 {
+		return 0
+	}
+	return float64(equity) / float64(totalAssets) * 100
+}
+
+// 自己資本が 0 以下の場合は 0 を返す
+func deRatio(debt int, equity int) float64 {
+	if equity <= 0 {
+		return 0
+	}
+	return float64(debt) / float64(equity)
+}
+
+// 当期純利益として扱う項目
+var NetIncomeTitles = []string{
+	"当期純利益",
+	"当期純損失（△）",
+	"当期純利益又は当期純損失（△）",
+	"当期利益",
+	"当期利益又は当期損失（△）",
+}
+
+// 包括利益として扱う項目
+var ComprehensiveIncomeTitles = []string{
+	"包括利益",
+	"当期包括利益",
+	"当期包括利益合計",
+	"包括利益合計",
+}
+
+// 1株当たり当期純利益の要素 (日本基準, IFRS の順)
+var EPSElements = []string{
+	"jpcrp_cor:BasicEarningsLossPerShareSummaryOfBusinessResults",
+	"jpcrp_cor:BasicEarningsLossPerShareIFRSSummaryOfBusinessResults",
+}
+
+// 潜在株式調整後1株当たり当期純利益の要素 (日本基準, IFRS の順)
+var DilutedEPSElements = []string{
+	"jpcrp_cor:DilutedEarningsPerShareSummaryOfBusinessResults",
+	"jpcrp_cor:DilutedEarningsLossPerShareIFRSSummaryOfBusinessResults",
+}
+
+/*
+XBRL インスタンスの事実のうち「主要な経営指標等の推移」から EPS を取得し、PL サマリーとファンダメンタルズに設定する
+@params
+
+	consolidated: 連結の値を使うかどうか (false の場合は個別の値)
+*/
+func UpdateEPS(facts []Fact, consolidated bool, plSummary *PLSummary, fundamental *Fundamental) {
+	plSummary.EPS = FindFloatTitleValue(facts, EPSElements, consolidated)
+	plSummary.DilutedEPS = FindFloatTitleValue(facts, DilutedEPSElements, consolidated)
+	if fundamental != nil {
+		fundamental.EPS = plSummary.EPS.Current
+	}
+}
+
+/*
+前期・当期の値を要素の優先順に探す
+*/
+func FindFloatTitleValue(facts []Fact, elements []string, consolidated bool) FloatTitleValue {
+	suffix := ""
+	if !consolidated {
+		suffix = "_NonConsolidatedMember"
+	}
+	for _, element := range elements {
+		previousFact, hasPrevious := FindFact(facts, element, "Prior1YearDuration"+suffix)
+		currentFact, hasCurrent := FindFact(facts, element, "CurrentYearDuration"+suffix)
+		if !hasPrevious && !hasCurrent {
+			continue
+		}
+		var value FloatTitleValue
+		value.Previous, _ = strconv.ParseFloat(previousFact.Value, 64)
+		value.Current, _ = strconv.ParseFloat(currentFact.Value, 64)
+		return value
+	}
+	return FloatTitleValue{}
+}
+
+// 配当金の支払額として扱う項目 (非支配株主への配当金は除く)
+var DividendsPaidTitles = []string{
+	"配当金の支払額",
+	"親会社による配当金の支払額",
+	"親会社の所有者への配当金の支払額",
+}
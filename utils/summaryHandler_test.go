@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestUpdateBSRatios(t *testing.T) {
+	tests := []struct {
+		name            string
+		summary         Summary
+		wantEquityRatio FloatTitleValue
+		wantDERatio     FloatTitleValue
+	}{
+		{
+			name: "日本基準 (新株予約権・非支配株主持分を除く)",
+			summary: Summary{
+				TotalAssets:             TitleValue{Previous: 1000, Current: 1000},
+				NetAssets:               TitleValue{Previous: 500, Current: 600},
+				SubscriptionRights:      TitleValue{Previous: 50, Current: 50},
+				NonControllingInterests: TitleValue{Previous: 50, Current: 50},
+				ShortTermBorrowings:     TitleValue{Previous: 100, Current: 100},
+			},
+			wantEquityRatio: FloatTitleValue{Previous: 40, Current: 50},
+			wantDERatio:     FloatTitleValue{Previous: 0.25, Current: 0.2},
+		},
+		{
+			name: "IFRS (親会社の所有者に帰属する持分合計)",
+			summary: Summary{
+				TotalAssets:             TitleValue{Previous: 1000, Current: 1000},
+				NetAssets:               TitleValue{Previous: 500, Current: 600},
+				NonControllingInterests: TitleValue{Previous: 100, Current: 100},
+				OwnersEquity:            TitleValue{Previous: 400, Current: 500},
+				BondsAndBorrowings:      TitleValue{Previous: 200, Current: 100},
+			},
+			wantEquityRatio: FloatTitleValue{Previous: 40, Current: 50},
+			wantDERatio:     FloatTitleValue{Previous: 0.5, Current: 0.2},
+		},
+		{
+			name: "純資産が取得できない期は計算しない",
+			summary: Summary{
+				TotalAssets:             TitleValue{Previous: 1000, Current: 1000},
+				NetAssets:               TitleValue{Current: 600},
+				NonControllingInterests: TitleValue{Previous: 100, Current: 100},
+			},
+			wantEquityRatio: FloatTitleValue{Previous: 0, Current: 50},
+			wantDERatio:     FloatTitleValue{Previous: 0, Current: 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := tt.summary
+			var fundamental Fundamental
+			UpdateBSRatios(&summary, &fundamental)
+			if !floatTitleValueEqual(summary.EquityRatio, tt.wantEquityRatio) {
+				t.Errorf("EquityRatio = %+v, want %+v", summary.EquityRatio, tt.wantEquityRatio)
+			}
+			if !floatTitleValueEqual(summary.DERatio, tt.wantDERatio) {
+				t.Errorf("DERatio = %+v, want %+v", summary.DERatio, tt.wantDERatio)
+			}
+			if fundamental.EquityRatio != summary.EquityRatio.Current {
+				t.Errorf("fundamental.EquityRatio = %v, want %v", fundamental.EquityRatio, summary.EquityRatio.Current)
+			}
+		})
+	}
+}
+
+// IFRS の連結財政状態計算書の資本合計を純資産とする
+func TestUpdateEverySummaryIFRSEquity(t *testing.T) {
+	html := "<table>" +
+		statementTestRow("資産合計", "1,000", "1,000") +
+		statementTestRow("親会社の所有者に帰属する持分合計", "400", "500") +
+		statementTestRow("非支配持分", "100", "100") +
+		statementTestRow("資本合計", "500", "600") +
+		statementTestRow("負債及び資本合計", "1,000", "1,000") +
+		"</table>"
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summary Summary
+	var fundamental Fundamental
+	UpdateEverySummary(doc, "", "", "bs", &summary, nil, nil, &fundamental, nil, &InspectStatement{})
+	if summary.NetAssets != (TitleValue{Previous: 500, Current: 600}) || fundamental.NetAssets != 600 {
+		t.Errorf("NetAssets = %+v, fundamental.NetAssets = %d", summary.NetAssets, fundamental.NetAssets)
+	}
+	if summary.OwnersEquity != (TitleValue{Previous: 400, Current: 500}) {
+		t.Errorf("OwnersEquity = %+v", summary.OwnersEquity)
+	}
+	if equity := summary.Equity(); equity != summary.OwnersEquity {
+		t.Errorf("Equity() = %+v, want %+v", equity, summary.OwnersEquity)
+	}
+}
+
+// 貸借対照表の科目を勘定科目の辞書で判定する
+func TestUpdateEverySummaryBSLabels(t *testing.T) {
+	html := "<table>" +
+		// 値のない見出し行
+		"<tr><td>\n<p>流動資産</p>\n</td><td></td><td></td></tr>\n" +
+		statementTestRow("現金及び預金", "100", "200") +
+		statementTestRow("流動資産合計", "300", "400") +
+		statementTestRow("資産合計", "1,000", "1,000") +
+		statementTestRow("負債合計", "500", "400") +
+		"<tr><td>\n<p>利益剰余金</p>\n</td><td></td><td></td></tr>\n" +
+		statementTestRow("利益準備金", "10", "10") +
+		statementTestRow("利益剰余金", "40", "50") +
+		statementTestRow("利益剰余金合計", "50", "60") +
+		statementTestRow("自己株式", "△5", "△6") +
+		// 内訳の行 (インデントが深い)
+		"<tr><td style=\"padding-left:16px\">\n<p>自己株式</p>\n</td><td>\n<p>△1</p>\n</td><td>\n<p>△1</p>\n</td></tr>\n" +
+		statementTestRow("純資産合計", "500", "600") +
+		"</table>"
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summary Summary
+	var fundamental Fundamental
+	UpdateEverySummary(doc, "", "", "bs", &summary, nil, nil, &fundamental, nil, &InspectStatement{})
+	tests := []struct {
+		name string
+		got  TitleValue
+		want TitleValue
+	}{
+		{"CashAndDeposits", summary.CashAndDeposits, TitleValue{Previous: 100, Current: 200}},
+		{"CurrentAssets", summary.CurrentAssets, TitleValue{Previous: 300, Current: 400}},
+		{"TotalAssets", summary.TotalAssets, TitleValue{Previous: 1000, Current: 1000}},
+		{"Liabilities", summary.Liabilities, TitleValue{Previous: 500, Current: 400}},
+		{"RetainedEarnings", summary.RetainedEarnings, TitleValue{Previous: 50, Current: 60}},
+		{"TreasuryStock", summary.TreasuryStock, TitleValue{Previous: -5, Current: -6}},
+		{"NetAssets", summary.NetAssets, TitleValue{Previous: 500, Current: 600}},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %+v, want %+v", tt.name, tt.got, tt.want)
+		}
+	}
+	if fundamental.NetAssets != 600 || fundamental.Liabilities != 400 {
+		t.Errorf("fundamental = %+v", fundamental)
+	}
+}
+
+// 財務諸表の表の行 (EDINET の TextBlock と同じくセルの中で改行する)
+func statementTestRow(title string, previous string, current string) string {
+	return "<tr><td>\n<p>" + title + "</p>\n</td><td>\n<p>" + previous + "</p>\n</td><td>\n<p>" + current + "</p>\n</td></tr>\n"
+}
+
+func floatTitleValueEqual(a FloatTitleValue, b FloatTitleValue) bool {
+	return math.Abs(a.Previous-b.Previous) < 1e-9 && math.Abs(a.Current-b.Current) < 1e-9
+}
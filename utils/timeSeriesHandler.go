@@ -0,0 +1,305 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 時系列データのファイル名 ({EDINETコード}/timeseries.json)
+const TimeSeriesFileName = "timeseries.json"
+
+// 同じ企業の時系列データの読み込み〜書き込みを並列で行わないようにする (EDINET コードごとの *sync.Mutex)
+var timeSeriesLocks sync.Map
+
+func timeSeriesLock(EDINETCode string) *sync.Mutex {
+	lock, _ := timeSeriesLocks.LoadOrStore(EDINETCode, &sync.Mutex{})
+	return lock.(*sync.Mutex)
+}
+
+/*
+レポートのサマリーの値 (前期・当期) を企業ごとの時系列データに統合して S3 に登録する
+連結・個別のサマリーをまとめて統合し、PL, CF はバリデーションに失敗した場合は統合しない
+*/
+func RegisterTimeSeries(docID string, dateKey string, EDINETCode string, companyName string, periodStart string, periodEnd string, sources []TimeSeriesSource) {
+	lock := timeSeriesLock(EDINETCode)
+	lock.Lock()
+	defer lock.Unlock()
+
+	key := fmt.Sprintf("%s/%s", EDINETCode, TimeSeriesFileName)
+	timeSeries, err := GetTimeSeries(key)
+	if err != nil {
+		ErrMsg = "時系列データ取得エラー: "
+		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+		return
+	}
+	timeSeries.EDINETCode = EDINETCode
+	timeSeries.CompanyName = companyName
+
+	var restatements []string
+	for _, source := range sources {
+		values, scopes, units := source.TimeSeriesValues()
+		restatements = append(restatements, MergeTimeSeries(&timeSeries, docID, periodStart, periodEnd, values, scopes, units)...)
+	}
+
+	body, err := json.MarshalIndent(timeSeries, "", "  ")
+	if err != nil {
+		ErrMsg = "時系列データ JSON 作成エラー: "
+		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+		return
+	}
+	err = PutJSONObject(S3Client, BucketName, key, body)
+	if err != nil {
+		ErrMsg = "時系列データ送信エラー: "
+		RegisterFailedJson(docID, dateKey, ErrMsg+err.Error())
+		return
+	}
+	if len(restatements) > 0 {
+		fmt.Printf("「%s」の前期の値が修正されています (%s) 📝\n", companyName, strings.Join(restatements, ", "))
+	}
+	fmt.Printf("「%s」の時系列データを登録しました📈 (%s)\n", companyName, key)
+}
+
+/*
+時系列データに統合する値と statement (bs, pl, cf) ごとの連結・個別、単位を返す
+*/
+func (source *TimeSeriesSource) TimeSeriesValues() (map[string]TitleValue, map[string]string, map[string]string) {
+	scopes := map[string]string{"bs": source.Summary.Scope, "pl": source.PLSummary.Scope, "cf": source.CFSummary.Scope}
+	units := map[string]string{"bs": source.Summary.UnitString, "pl": source.PLSummary.UnitString, "cf": source.CFSummary.UnitString}
+	values := SummaryTitleValues(&source.Summary, &source.PLSummary, &source.CFSummary)
+	for key := range values {
+		statement, _, _ := strings.Cut(key, ".")
+		if statement == "pl" && !source.IsPLSummaryValid || statement == "cf" && !source.IsCFSummaryValid {
+			delete(values, key)
+		}
+	}
+	return values, scopes, units
+}
+
+/*
+S3 から時系列データを取得する
+存在しない場合は空の時系列データを返す
+DryRun の場合は書き出し済みのファイルを優先する
+*/
+func GetTimeSeries(key string) (TimeSeries, error) {
+	timeSeries := TimeSeries{Items: make(map[string][]TimeSeriesPoint)}
+	var body []byte
+	if DryRun == "true" {
+		dryRunBody, err := os.ReadFile(filepath.Join(DryRunDir, BucketName, key))
+		if err == nil {
+			body = dryRunBody
+		}
+	}
+	if body == nil {
+		exists, err := CheckFileExists(S3Client, BucketName, key)
+		if err != nil {
+			return timeSeries, err
+		}
+		if !exists {
+			return timeSeries, nil
+		}
+		output, err := GetS3Object(S3Client, BucketName, key)
+		if err != nil {
+			return timeSeries, err
+		}
+		defer output.Body.Close()
+		body, err = io.ReadAll(output.Body)
+		if err != nil {
+			return timeSeries, err
+		}
+	}
+	err := json.Unmarshal(body, &timeSeries)
+	if err != nil {
+		return timeSeries, err
+	}
+	if timeSeries.Items == nil {
+		timeSeries.Items = make(map[string][]TimeSeriesPoint)
+	}
+	return timeSeries, nil
+}
+
+/*
+レポートの値を時系列データに統合する
+当期の値は期末日 (periodEnd)、前期の値は当期の期首日の前日を期末日として登録する
+値は円に換算し、0 の値は登録しない
+@params
+
+	values: 項目 (例: pl.sales) ごとの値
+	scopes: statement (bs, pl, cf) ごとの連結・個別
+	units:  statement ごとの単位
+
+@returns
+
+	前期の値が修正された項目 (例: pl.sales 2023-03-31)
+*/
+func MergeTimeSeries(timeSeries *TimeSeries, docID string, periodStart string, periodEnd string, values map[string]TitleValue, scopes map[string]string, units map[string]string) []string {
+	previousStart, previousEnd, hasPrevious := PreviousPeriod(periodStart)
+	var restatements []string
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := values[key]
+		statement, _, _ := strings.Cut(key, ".")
+		divisor := int(UnitDivisor(units[statement]))
+		point := TimeSeriesPoint{
+			Scope: scopes[statement],
+			Unit:  units[statement],
+			DocID: docID,
+		}
+		if value.Current != 0 {
+			current := point
+			current.PeriodStart = periodStart
+			current.PeriodEnd = periodEnd
+			current.Value = value.Current * divisor
+			timeSeries.Items[key] = MergeTimeSeriesPoint(timeSeries.Items[key], current)
+		}
+		if value.Previous != 0 && hasPrevious {
+			previous := point
+			previous.PeriodStart = previousStart
+			previous.PeriodEnd = previousEnd
+			previous.Value = value.Previous * divisor
+			previous.IsPrevious = true
+			var restated bool
+			timeSeries.Items[key], restated = mergePreviousPoint(timeSeries.Items[key], previous)
+			if restated {
+				restatements = append(restatements, fmt.Sprintf("%s %s", key, previousEnd))
+			}
+		}
+	}
+	return restatements
+}
+
+/*
+当期の値を時系列データに統合する
+同じ期間・連結/個別の値がある場合は上書きする (訂正報告書など)
+既存の値が翌期のレポートの前期の値で、当期として報告された値と異なる場合は修正として記録する
+*/
+func MergeTimeSeriesPoint(points []TimeSeriesPoint, point TimeSeriesPoint) []TimeSeriesPoint {
+	index := findTimeSeriesPoint(points, point)
+	if index < 0 {
+		return sortTimeSeriesPoints(append(points, point))
+	}
+	existing := points[index]
+	switch {
+	case existing.IsPrevious && !sameTimeSeriesValue(existing, point):
+		// 後から処理した当期の値を修正前の値とし、翌期のレポートの値を修正後の値とする
+		existing.PeriodStart = point.PeriodStart
+		existing.IsPrevious = false
+		existing.Restatement = &TimeSeriesRestatement{
+			OriginalValue: point.Value,
+			OriginalDocID: point.DocID,
+			RestatedValue: existing.Value,
+			RestatedDocID: existing.DocID,
+		}
+		points[index] = existing
+	case existing.Restatement != nil:
+		// 修正前の値のみ更新する
+		existing.Restatement.OriginalValue = point.Value
+		existing.Restatement.OriginalDocID = point.DocID
+		if sameTimeSeriesValue(existing, point) {
+			existing.Restatement = nil
+		}
+		points[index] = existing
+	default:
+		points[index] = point
+	}
+	return points
+}
+
+/*
+前期の値を時系列データに統合する
+@returns
+
+	当期として報告された値と異なる場合は true
+*/
+func mergePreviousPoint(points []TimeSeriesPoint, point TimeSeriesPoint) ([]TimeSeriesPoint, bool) {
+	index := findTimeSeriesPoint(points, point)
+	if index < 0 {
+		return sortTimeSeriesPoints(append(points, point)), false
+	}
+	existing := points[index]
+	if existing.IsPrevious {
+		// 前期の値のみの場合は上書きする
+		points[index] = point
+		return points, false
+	}
+
+	original := existing
+	if existing.Restatement != nil {
+		original.Value = existing.Restatement.OriginalValue
+		original.DocID = existing.Restatement.OriginalDocID
+	}
+	if sameTimeSeriesValue(original, point) {
+		// 修正がない場合は当期として報告された値に戻す
+		existing.Value = original.Value
+		existing.DocID = original.DocID
+		existing.Restatement = nil
+		points[index] = existing
+		return points, false
+	}
+	existing.Value = point.Value
+	existing.DocID = point.DocID
+	existing.Restatement = &TimeSeriesRestatement{
+		OriginalValue: original.Value,
+		OriginalDocID: original.DocID,
+		RestatedValue: point.Value,
+		RestatedDocID: point.DocID,
+	}
+	points[index] = existing
+	return points, true
+}
+
+func findTimeSeriesPoint(points []TimeSeriesPoint, point TimeSeriesPoint) int {
+	for i, p := range points {
+		if p.PeriodEnd == point.PeriodEnd && p.Scope == point.Scope {
+			return i
+		}
+	}
+	return -1
+}
+
+/*
+単位の違いによる丸めを考慮して同じ値かどうか判定する
+(例: 百万円単位のレポートと千円単位のレポートの値)
+*/
+func sameTimeSeriesValue(a TimeSeriesPoint, b TimeSeriesPoint) bool {
+	tolerance := max(UnitDivisor(a.Unit), UnitDivisor(b.Unit))
+	if tolerance == 1 {
+		tolerance = 0
+	}
+	return withinTolerance(a.Value, b.Value, int(tolerance))
+}
+
+// 期末日・連結/個別の順に並べる
+func sortTimeSeriesPoints(points []TimeSeriesPoint) []TimeSeriesPoint {
+	sort.SliceStable(points, func(i, j int) bool {
+		if points[i].PeriodEnd != points[j].PeriodEnd {
+			return points[i].PeriodEnd < points[j].PeriodEnd
+		}
+		return points[i].Scope < points[j].Scope
+	})
+	return points
+}
+
+/*
+当期の期首日から前期の期間を求める
+例: 2024-04-01 → 2023-04-01, 2024-03-31
+*/
+func PreviousPeriod(periodStart string) (string, string, bool) {
+	start, err := time.Parse("2006-01-02", periodStart)
+	if err != nil {
+		return "", "", false
+	}
+	previousStart := start.AddDate(-1, 0, 0).Format("2006-01-02")
+	previousEnd := start.AddDate(0, 0, -1).Format("2006-01-02")
+	return previousStart, previousEnd, true
+}
@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+// 時系列データに統合するレポート
+type timeSeriesTestReport struct {
+	docID       string
+	periodStart string
+	periodEnd   string
+	unit        string
+	sales       TitleValue
+}
+
+// 2022年度 (2022-04-01 〜 2023-03-31) と 2023年度のレポート
+var (
+	timeSeriesReportA = timeSeriesTestReport{"A", "2022-04-01", "2023-03-31", "百万円", TitleValue{Previous: 90, Current: 100}}
+	// 2022年度の売上高を 100 から 105 に修正
+	timeSeriesReportB = timeSeriesTestReport{"B", "2023-04-01", "2024-03-31", "百万円", TitleValue{Previous: 105, Current: 120}}
+	// 千円単位で、2022年度の売上高は百万円単位の 100 と丸めの違いのみ
+	timeSeriesReportBThousand = timeSeriesTestReport{"B", "2023-04-01", "2024-03-31", "千円", TitleValue{Previous: 100400, Current: 120000}}
+)
+
+func TestMergeTimeSeries(t *testing.T) {
+	const million = 1000000
+	previousPoint := TimeSeriesPoint{PeriodStart: "2021-04-01", PeriodEnd: "2022-03-31", Scope: ScopeConsolidated, Unit: "百万円", Value: 90 * million, DocID: "A", IsPrevious: true}
+	pointA := TimeSeriesPoint{PeriodStart: "2022-04-01", PeriodEnd: "2023-03-31", Scope: ScopeConsolidated, Unit: "百万円", Value: 100 * million, DocID: "A"}
+	pointB := TimeSeriesPoint{PeriodStart: "2023-04-01", PeriodEnd: "2024-03-31", Scope: ScopeConsolidated, Unit: "百万円", Value: 120 * million, DocID: "B"}
+	restatedPointA := pointA
+	restatedPointA.Value = 105 * million
+	restatedPointA.DocID = "B"
+	restatedPointA.Restatement = &TimeSeriesRestatement{OriginalValue: 100 * million, OriginalDocID: "A", RestatedValue: 105 * million, RestatedDocID: "B"}
+
+	tests := []struct {
+		name             string
+		reports          []timeSeriesTestReport
+		want             []TimeSeriesPoint
+		wantRestatements []string // 最後のレポートで修正された項目
+	}{
+		{
+			name:             "期の順に処理",
+			reports:          []timeSeriesTestReport{timeSeriesReportA, timeSeriesReportB},
+			want:             []TimeSeriesPoint{previousPoint, restatedPointA, pointB},
+			wantRestatements: []string{"pl.sales 2023-03-31"},
+		},
+		{
+			name:    "翌期のレポートを先に処理",
+			reports: []timeSeriesTestReport{timeSeriesReportB, timeSeriesReportA},
+			want:    []TimeSeriesPoint{previousPoint, restatedPointA, pointB},
+		},
+		{
+			name:    "同じレポートを再処理",
+			reports: []timeSeriesTestReport{timeSeriesReportA, timeSeriesReportA},
+			want:    []TimeSeriesPoint{previousPoint, pointA},
+		},
+		{
+			name:    "修正後に修正前のレポートを再処理",
+			reports: []timeSeriesTestReport{timeSeriesReportA, timeSeriesReportB, timeSeriesReportA},
+			want:    []TimeSeriesPoint{previousPoint, restatedPointA, pointB},
+		},
+		{
+			name:             "修正後に翌期のレポートを再処理",
+			reports:          []timeSeriesTestReport{timeSeriesReportA, timeSeriesReportB, timeSeriesReportB},
+			want:             []TimeSeriesPoint{previousPoint, restatedPointA, pointB},
+			wantRestatements: []string{"pl.sales 2023-03-31"},
+		},
+		{
+			name:    "単位の違いによる丸め",
+			reports: []timeSeriesTestReport{timeSeriesReportA, timeSeriesReportBThousand},
+			want: []TimeSeriesPoint{
+				previousPoint,
+				pointA,
+				{PeriodStart: "2023-04-01", PeriodEnd: "2024-03-31", Scope: ScopeConsolidated, Unit: "千円", Value: 120 * million, DocID: "B"},
+			},
+		},
+		{
+			name:    "単位の違いによる丸め (翌期のレポートを先に処理)",
+			reports: []timeSeriesTestReport{timeSeriesReportBThousand, timeSeriesReportA},
+			want: []TimeSeriesPoint{
+				previousPoint,
+				pointA,
+				{PeriodStart: "2023-04-01", PeriodEnd: "2024-03-31", Scope: ScopeConsolidated, Unit: "千円", Value: 120 * million, DocID: "B"},
+			},
+		},
+		{
+			name:    "0 の値は登録しない",
+			reports: []timeSeriesTestReport{{"A", "2022-04-01", "2023-03-31", "百万円", TitleValue{Previous: 0, Current: 100}}},
+			want:    []TimeSeriesPoint{pointA},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeSeries := TimeSeries{Items: make(map[string][]TimeSeriesPoint)}
+			var restatements []string
+			for _, report := range tt.reports {
+				values := map[string]TitleValue{"pl.sales": report.sales}
+				scopes := map[string]string{"pl": ScopeConsolidated}
+				units := map[string]string{"pl": report.unit}
+				restatements = MergeTimeSeries(&timeSeries, report.docID, report.periodStart, report.periodEnd, values, scopes, units)
+			}
+			if got := timeSeries.Items["pl.sales"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Items[pl.sales] = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(restatements, tt.wantRestatements) {
+				t.Errorf("restatements = %v, want %v", restatements, tt.wantRestatements)
+			}
+		})
+	}
+}
+
+func TestTimeSeriesSourceValues(t *testing.T) {
+	source := TimeSeriesSource{
+		Summary:          Summary{Scope: ScopeSolo, UnitString: "百万円", TreasuryStock: TitleValue{Previous: -10, Current: -20}},
+		PLSummary:        PLSummary{Scope: ScopeSolo, ComprehensiveIncome: TitleValue{Current: 30}, IndustryItems: map[string]TitleValue{"ordinary_income": {Current: 40}}},
+		CFSummary:        CFSummary{Scope: ScopeSolo, OperatingCF: TitleValue{Current: 50}},
+		IsPLSummaryValid: true,
+	}
+	values, scopes, units := source.TimeSeriesValues()
+	if got := values["bs.treasury_stock"]; got != source.Summary.TreasuryStock {
+		t.Errorf("bs.treasury_stock = %+v, want %+v", got, source.Summary.TreasuryStock)
+	}
+	if got := values["pl.comprehensive_income"]; got.Current != 30 {
+		t.Errorf("pl.comprehensive_income = %+v, want current 30", got)
+	}
+	if got := values["pl.industry_items.ordinary_income"]; got.Current != 40 {
+		t.Errorf("pl.industry_items.ordinary_income = %+v, want current 40", got)
+	}
+	// CF はバリデーションに失敗しているため統合しない
+	if _, ok := values["cf.operating_cf"]; ok {
+		t.Errorf("cf.operating_cf should be excluded when CF summary is invalid")
+	}
+	if scopes["bs"] != ScopeSolo || units["bs"] != "百万円" {
+		t.Errorf("scopes = %v, units = %v", scopes, units)
+	}
+}
+
+func TestPreviousPeriod(t *testing.T) {
+	tests := []struct {
+		periodStart string
+		wantStart   string
+		wantEnd     string
+		wantOK      bool
+	}{
+		{"2024-04-01", "2023-04-01", "2024-03-31", true},
+		{"2024-01-01", "2023-01-01", "2023-12-31", true},
+		{"", "", "", false},
+	}
+	for _, tt := range tests {
+		start, end, ok := PreviousPeriod(tt.periodStart)
+		if start != tt.wantStart || end != tt.wantEnd || ok != tt.wantOK {
+			t.Errorf("PreviousPeriod(%q) = (%q, %q, %v), want (%q, %q, %v)", tt.periodStart, start, end, ok, tt.wantStart, tt.wantEnd, tt.wantOK)
+		}
+	}
+}
@@ -16,14 +16,30 @@ type User struct {
 }
 
 type Company struct {
-	ID         string    `json:"id" dynamodbav:"id"`
-	CreatedAt  time.Time `json:"createdAt" dynamodbav:"createdAt"`
-	UpdatedAt  time.Time `json:"updatedAt" dynamodbav:"updatedAt"`
-	Name       string    `json:"name" dynamodbav:"name"`
-	EDINETCode string    `json:"edinetCode" dynamodbav:"edinetCode"`
+	ID           string    `json:"id" dynamodbav:"id"`
+	CreatedAt    time.Time `json:"createdAt" dynamodbav:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt" dynamodbav:"updatedAt"`
+	Name         string    `json:"name" dynamodbav:"name"`
+	EDINETCode   string    `json:"edinetCode" dynamodbav:"edinetCode"`
 	SecurityCode string    `json:"securityCode" dynamodbav:"securityCode"`
-	BS         int       `json:"bs" dynamodbav:"bs"`
-	PL         int       `json:"pl" dynamodbav:"pl"`
+	PdfKey       string    `json:"pdfKey" dynamodbav:"pdfKey"` // EDINET バケットの PDF のキー
+	BS           int       `json:"bs" dynamodbav:"bs"`
+	PL           int       `json:"pl" dynamodbav:"pl"`
+	CompanyOverview
+}
+
+// 有価証券報告書の DEI・企業の概況から取得する企業情報
+type CompanyOverview struct {
+	NameEn             string  `json:"nameEn" dynamodbav:"nameEn"`                         // 英語の企業名
+	FiscalYearEnd      string  `json:"fiscalYearEnd" dynamodbav:"fiscalYearEnd"`           // 決算日 (例: 2024-03-31)
+	AccountingStandard string  `json:"accountingStandard" dynamodbav:"accountingStandard"` // 会計基準 (Japan GAAP, IFRS, US GAAP)
+	IsConsolidated     bool    `json:"isConsolidated" dynamodbav:"isConsolidated"`         // 連結財務諸表の有無
+	Employees          int     `json:"employees" dynamodbav:"employees"`                   // 従業員数
+	AverageSalary      int     `json:"averageSalary" dynamodbav:"averageSalary"`           // 平均年間給与 (円)
+	AverageAge         float64 `json:"averageAge" dynamodbav:"averageAge"`                 // 平均年齢 (歳)
+	HeadOfficeAddress  string  `json:"headOfficeAddress" dynamodbav:"headOfficeAddress"`   // 本店の所在の場所
+	IndustryCode       string  `json:"industryCode" dynamodbav:"industryCode"`             // 別記事業の業種コード (例: bk1)
+	Representative     string  `json:"representative" dynamodbav:"representative"`         // 代表者の役職氏名
 }
 
 type Title struct {
@@ -112,14 +128,28 @@ type Identifier struct {
 	Value  string `xml:",chardata"`
 }
 
+// <xbrldi:explicitMember> 要素
+type ExplicitMember struct {
+	Dimension string `xml:"dimension,attr"` // 例: jpcrp_cor:OperatingSegmentsAxis
+	Value     string `xml:",chardata"`      // 例: jpcrp030000-asr_E00001-000:AutomobileReportableSegmentsMember
+}
+
+// <xbrli:segment> 要素
+type Segment struct {
+	ExplicitMembers []ExplicitMember `xml:"explicitMember"`
+}
+
 // <xbrli:entity> 要素
 type Entity struct {
 	Identifier Identifier `xml:"identifier"`
+	Segment    Segment    `xml:"segment"`
 }
 
 // <xbrli:period> 要素
 type Period struct {
-	Instant string `xml:"instant"`
+	Instant   string `xml:"instant"`
+	StartDate string `xml:"startDate"`
+	EndDate   string `xml:"endDate"`
 }
 
 // <xbrli:context> 要素
@@ -161,12 +191,65 @@ type XBRL struct {
 	NotesFinancialInformationOfInvestmentTrustManagementCompanyEtcTextBlock BalanceSheet     `xml:"NotesFinancialInformationOfInvestmentTrustManagementCompanyEtcTextBlock"`
 }
 
+// XBRL インスタンスの種類
+const (
+	XBRLKindMain       = "main"       // 有価証券報告書本体
+	XBRLKindAudit      = "audit"      // 監査報告書
+	XBRLKindAttachment = "attachment" // その他
+)
+
+// ZIP 内の XBRL インスタンス
+type XBRLInstance struct {
+	Path string // ZIP 内のパス
+	Kind string // main, audit, attachment
+	Body []byte
+}
+
+// ZIP 内の インライン XBRL (PublicDoc 配下の *_ixbrl.htm)
+type IXBRLDocument struct {
+	Path string // ZIP 内のパス
+	Body []byte
+}
+
+// ZIP から取得した XBRL インスタンス一覧
+type XBRLArchive struct {
+	Main        XBRLInstance
+	Audits      []XBRLInstance
+	Attachments []XBRLInstance
+	Manuscripts []IXBRLDocument // 本文のインライン XBRL
+	LabelFiles  []ArchiveFile   // 提出者別タクソノミの名称リンクベース (*_lab.xml, *_lab-en.xml)
+}
+
+// XBRL の事実 (要素ごとの値)
+type Fact struct {
+	Name       string `json:"name"` // 例: jppfs_cor:NetSales
+	ContextRef string `json:"context_ref"`
+	UnitRef    string `json:"unit_ref"`
+	Decimals   string `json:"decimals"`
+	Value      string `json:"value"` // 数値の場合は scale, sign を適用した値
+	IsNil      bool   `json:"is_nil"`
+}
+
+// 2 つの事実一覧で値が異なるもの
+type FactDiff struct {
+	Name        string `json:"name"`
+	ContextRef  string `json:"context_ref"`
+	BaseValue   string `json:"base_value"`
+	TargetValue string `json:"target_value"`
+}
+
 // 項目ごとの値
 type TitleValue struct {
 	Previous int `json:"previous"`
 	Current  int `json:"current"`
 }
 
+// 1株当たりの値など小数を含む値
+type FloatTitleValue struct {
+	Previous float64 `json:"previous"`
+	Current  float64 `json:"current"`
+}
+
 type MyError interface {
 	Error() string
 }
@@ -217,6 +300,7 @@ type Result struct {
 	XbrlFlag             string `json:"xbrlFlag"`
 	PdfFlag              string `json:"pdfFlag"`
 	AttachDocFlag        string `json:"attachDocFlag"`
+	EnglishDocFlag       string `json:"englishDocFlag"`
 	CsvFlag              string `json:"csvFlag"`
 	LegalStatus          string `json:"legalStatus"`
 }
@@ -230,23 +314,42 @@ type Report struct {
 B/S の値のうち比例縮尺図に使うもの
 */
 type Summary struct {
-	CompanyName               string     `json:"company_name"`
-	PeriodStart               string     `json:"period_start"`
-	PeriodEnd                 string     `json:"period_end"`
-	UnitString                string     `json:"unit_string"`                  // 単位
-	CurrentAssets             TitleValue `json:"current_assets"`               // 流動資産
-	TangibleAssets            TitleValue `json:"tangible_assets"`              // 有形固定資産
-	IntangibleAssets          TitleValue `json:"intangible_assets"`            // 無形固定資産
-	InvestmentsAndOtherAssets TitleValue `json:"investments_and_other_assets"` // 投資その他の資産
-	CurrentLiabilities        TitleValue `json:"current_liabilities"`          // 流動負債
-	FixedLiabilities          TitleValue `json:"fixed_liabilities"`            // 固定負債
-	NetAssets                 TitleValue `json:"net_assets"`                   // 純資産
+	CompanyName               string          `json:"company_name"`
+	PeriodStart               string          `json:"period_start"`
+	PeriodEnd                 string          `json:"period_end"`
+	Scope                     string          `json:"scope"`
+	UnitString                string          `json:"unit_string"`                  // 単位
+	CurrentAssets             TitleValue      `json:"current_assets"`               // 流動資産
+	TangibleAssets            TitleValue      `json:"tangible_assets"`              // 有形固定資産
+	IntangibleAssets          TitleValue      `json:"intangible_assets"`            // 無形固定資産
+	InvestmentsAndOtherAssets TitleValue      `json:"investments_and_other_assets"` // 投資その他の資産
+	CurrentLiabilities        TitleValue      `json:"current_liabilities"`          // 流動負債
+	FixedLiabilities          TitleValue      `json:"fixed_liabilities"`            // 固定負債
+	NetAssets                 TitleValue      `json:"net_assets"`                   // 純資産 (IFRS の場合は資本合計)
+	TotalAssets               TitleValue      `json:"total_assets"`                 // 資産合計
+	Liabilities               TitleValue      `json:"liabilities"`                  // 負債合計
+	ShareholdersEquity        TitleValue      `json:"shareholders_equity"`          // 株主資本合計
+	RetainedEarnings          TitleValue      `json:"retained_earnings"`            // 利益剰余金
+	TreasuryStock             TitleValue      `json:"treasury_stock"`               // 自己株式 (マイナスの値)
+	NonControllingInterests   TitleValue      `json:"non_controlling_interests"`    // 非支配株主持分
+	SubscriptionRights        TitleValue      `json:"subscription_rights"`          // 新株予約権
+	OwnersEquity              TitleValue      `json:"owners_equity"`                // 親会社の所有者に帰属する持分合計 (IFRS)
+	CashAndDeposits           TitleValue      `json:"cash_and_deposits"`            // 現金及び預金 (IFRS の場合は現金及び現金同等物)
+	Inventories               TitleValue      `json:"inventories"`                  // 棚卸資産 (商品及び製品、仕掛品、原材料及び貯蔵品の合計)
+	ShortTermBorrowings       TitleValue      `json:"short_term_borrowings"`        // 短期借入金
+	LongTermBorrowings        TitleValue      `json:"long_term_borrowings"`         // 長期借入金 (1年内返済予定の長期借入金を含む)
+	Bonds                     TitleValue      `json:"bonds"`                        // 社債 (1年内償還予定の社債を含む)
+	BondsAndBorrowings        TitleValue      `json:"bonds_and_borrowings"`         // 社債及び借入金 (IFRS、流動・非流動の合計)
+	NetCash                   TitleValue      `json:"net_cash"`                     // ネットキャッシュ (現金及び預金 - 有利子負債)
+	EquityRatio               FloatTitleValue `json:"equity_ratio"`                 // 自己資本比率 (%)
+	DERatio                   FloatTitleValue `json:"de_ratio"`                     // D/E レシオ (有利子負債 / 自己資本)
 }
 
 type PLSummary struct {
 	CompanyName         string     `json:"company_name"`
 	PeriodStart         string     `json:"period_start"`
 	PeriodEnd           string     `json:"period_end"`
+	Scope               string     `json:"scope"`
 	UnitString          string     `json:"unit_string"`
 	CostOfGoodsSold     TitleValue `json:"cost_of_goods_sold"`    // 売上原価
 	SGAndA              TitleValue `json:"sg_and_a"`              // 販売費及び一般管理費
@@ -257,33 +360,206 @@ type PLSummary struct {
 	OperatingCost       TitleValue `json:"operating_cost"`        // 営業費用 (一部の企業で使用)
 	HasOperatingCost    bool       `json:"has_operating_cost"`    // 営業費用が計上されているかどうか
 	// OperatingLoss             TitleValue `json:"operating_loss"` // 営業損失
+	NonOperatingIncome            TitleValue            `json:"non_operating_income"`              // 営業外収益
+	NonOperatingExpenses          TitleValue            `json:"non_operating_expenses"`            // 営業外費用
+	OrdinaryProfit                TitleValue            `json:"ordinary_profit"`                   // 経常利益
+	ExtraordinaryIncome           TitleValue            `json:"extraordinary_income"`              // 特別利益
+	ExtraordinaryLoss             TitleValue            `json:"extraordinary_loss"`                // 特別損失
+	ProfitBeforeTax               TitleValue            `json:"profit_before_tax"`                 // 税引前当期純利益 (税金等調整前当期純利益)
+	IncomeTaxes                   TitleValue            `json:"income_taxes"`                      // 法人税等
+	NetIncome                     TitleValue            `json:"net_income"`                        // 当期純利益
+	NetIncomeAttributableToOwners TitleValue            `json:"net_income_attributable_to_owners"` // 親会社株主に帰属する当期純利益
+	EPS                           FloatTitleValue       `json:"eps"`                               // 1株当たり当期純利益 (円)
+	DilutedEPS                    FloatTitleValue       `json:"diluted_eps"`                       // 潜在株式調整後1株当たり当期純利益 (円)
+	OtherComprehensiveIncome      TitleValue            `json:"other_comprehensive_income"`        // その他の包括利益合計
+	ComprehensiveIncome           TitleValue            `json:"comprehensive_income"`              // 包括利益
+	OwnersComprehensiveIncome     TitleValue            `json:"owners_comprehensive_income"`       // 親会社株主に係る包括利益
+	Industry                      string                `json:"industry"`                          // 業種別テンプレート (general, bank, insurance, securities)
+	IndustryItems                 map[string]TitleValue `json:"industry_items,omitempty"`          // 業種別テンプレートの勘定科目
 }
 
 type Fundamental struct {
-	CompanyName         string `json:"company_name"`
-	PeriodStart         string `json:"period_start"`
-	PeriodEnd           string `json:"period_end"`
-	Sales               int    `json:"sales"`
-	OperatingProfit     int    `json:"operating_profit"`
-	OperatingRevenue    int    `json:"operating_revenue"`     // 営業収益
-	HasOperatingRevenue bool   `json:"has_operating_revenue"` // 営業収益が計上されているかどうか
-	OperatingCost       int    `json:"operating_cost"`        // 営業費用 (一部の企業で使用)
-	HasOperatingCost    bool   `json:"has_operating_cost"`    // 営業費用が計上されているかどうか
-	Liabilities         int    `json:"liabilities"`
-	NetAssets           int    `json:"net_assets"`
+	CompanyName         string  `json:"company_name"`
+	PeriodStart         string  `json:"period_start"`
+	PeriodEnd           string  `json:"period_end"`
+	Sales               int     `json:"sales"`
+	OperatingProfit     int     `json:"operating_profit"`
+	OperatingRevenue    int     `json:"operating_revenue"`     // 営業収益
+	HasOperatingRevenue bool    `json:"has_operating_revenue"` // 営業収益が計上されているかどうか
+	OperatingCost       int     `json:"operating_cost"`        // 営業費用 (一部の企業で使用)
+	HasOperatingCost    bool    `json:"has_operating_cost"`    // 営業費用が計上されているかどうか
+	Liabilities         int     `json:"liabilities"`
+	NetAssets           int     `json:"net_assets"`
+	NetIncome           int     `json:"net_income"`       // 親会社株主に帰属する当期純利益 (ない場合は当期純利益)
+	EPS                 float64 `json:"eps"`              // 1株当たり当期純利益 (円)
+	Industry            string  `json:"industry"`         // 業種別テンプレート
+	IndustryRevenue     int     `json:"industry_revenue"` // 業種別テンプレートの収益 (銀行・保険は経常収益, 証券は営業収益)
+	IndustryProfit      int     `json:"industry_profit"`  // 業種別テンプレートの利益 (銀行・保険は経常利益, 証券は営業利益)
+	EquityRatio         float64 `json:"equity_ratio"`     // 自己資本比率 (%)
+	DERatio             float64 `json:"de_ratio"`         // D/E レシオ
 }
 
 type CFSummary struct {
-	CompanyName string     `json:"company_name"`
-	PeriodStart string     `json:"period_start"`
-	PeriodEnd   string     `json:"period_end"`
-	UnitString  string     `json:"unit_string"`
-	OperatingCF TitleValue `json:"operating_cf"` // 営業活動によるキャッシュ・フロー
-	InvestingCF TitleValue `json:"investing_cf"` // 投資活動によるキャッシュ・フロー
-	FinancingCF TitleValue `json:"financing_cf"` // 財務活動によるキャッシュ・フロー
-	// FreeCF TitleValue `json:"free_cf"` // フリーCF
-	StartCash TitleValue `json:"start_cash"` // 現金及び現金同等物の期首残高
-	EndCash   TitleValue `json:"end_cash"`   // 現金及び現金同等物の期末残高
+	CompanyName      string     `json:"company_name"`
+	PeriodStart      string     `json:"period_start"`
+	PeriodEnd        string     `json:"period_end"`
+	Scope            string     `json:"scope"`
+	UnitString       string     `json:"unit_string"`
+	OperatingCF      TitleValue `json:"operating_cf"`       // 営業活動によるキャッシュ・フロー
+	InvestingCF      TitleValue `json:"investing_cf"`       // 投資活動によるキャッシュ・フロー
+	FinancingCF      TitleValue `json:"financing_cf"`       // 財務活動によるキャッシュ・フロー
+	FreeCF           TitleValue `json:"free_cf"`            // フリーCF (営業CF + 投資CF)
+	StartCash        TitleValue `json:"start_cash"`         // 現金及び現金同等物の期首残高
+	EndCash          TitleValue `json:"end_cash"`           // 現金及び現金同等物の期末残高
+	NetChange        TitleValue `json:"net_change"`         // 現金及び現金同等物の増減額
+	Depreciation     TitleValue `json:"depreciation"`       // 減価償却費
+	Capex            TitleValue `json:"capex"`              // 有形固定資産の取得による支出
+	DividendsPaid    TitleValue `json:"dividends_paid"`     // 配当金の支払額
+	ShareBuybacks    TitleValue `json:"share_buybacks"`     // 自己株式の取得による支出
+	IsCashReconciled bool       `json:"is_cash_reconciled"` // 期首残高 + 増減額 = 期末残高 となっているかどうか
+}
+
+// 株主資本等変動計算書
+type SSSummary struct {
+	CompanyName              string     `json:"company_name"`
+	PeriodStart              string     `json:"period_start"`
+	PeriodEnd                string     `json:"period_end"`
+	Scope                    string     `json:"scope"`
+	UnitString               string     `json:"unit_string"`
+	EquityBeginning          TitleValue `json:"equity_beginning"`           // 当期首残高 (純資産合計)
+	Dividends                TitleValue `json:"dividends"`                  // 剰余金の配当
+	ShareBuybacks            TitleValue `json:"share_buybacks"`             // 自己株式の取得
+	NetIncome                TitleValue `json:"net_income"`                 // 親会社株主に帰属する当期純利益
+	OtherComprehensiveIncome TitleValue `json:"other_comprehensive_income"` // その他の包括利益
+	ComprehensiveIncome      TitleValue `json:"comprehensive_income"`       // 当期包括利益
+	NonShareholdersEquity    TitleValue `json:"non_shareholders_equity"`    // 株主資本以外の項目の当期変動額（純額）
+	EquityEnd                TitleValue `json:"equity_end"`                 // 当期末残高 (純資産合計)
+}
+
+// 監査報告書ごとの監査人と監査意見
+type AuditReport struct {
+	FileName    string `json:"file_name"`
+	AuditorName string `json:"auditor_name"` // 監査法人名
+	OpinionType string `json:"opinion_type"` // 無限定適正意見, 限定付適正意見, 不適正意見, 意見不表明
+}
+
+type AuditSummary struct {
+	CompanyName string        `json:"company_name"`
+	PeriodStart string        `json:"period_start"`
+	PeriodEnd   string        `json:"period_end"`
+	Audits      []AuditReport `json:"audits"`
+}
+
+// ZIP 内のファイル
+type ArchiveFile struct {
+	Path string
+	Body []byte
+}
+
+// 英文書類の財務諸表
+type EnglishStatement struct {
+	StatementType string   `json:"statement_type"` // BS, PL, CF
+	FileName      string   `json:"file_name"`
+	Labels        []string `json:"labels"` // 勘定科目 (英語)
+	HTML          string   `json:"html"`
+}
+
+type EnglishSummary struct {
+	CompanyName string             `json:"company_name"`
+	PeriodStart string             `json:"period_start"`
+	PeriodEnd   string             `json:"period_end"`
+	Statements  []EnglishStatement `json:"statements"`
+}
+
+// セグメントごとの売上高・利益
+type SegmentValue struct {
+	Axis   string     `json:"axis"`   // business (事業別) もしくは geography (地域別)
+	Member string     `json:"member"` // ディメンションのメンバー (例: jpcrp030000-asr_E00001-000:AutomobileReportableSegmentsMember)
+	Label  string     `json:"label"`  // セグメント名
+	Sales  TitleValue `json:"sales"`  // 売上高 (円)
+	Profit TitleValue `json:"profit"` // セグメント利益 (円)
+}
+
+type SegmentSummary struct {
+	CompanyName string         `json:"company_name"`
+	PeriodStart string         `json:"period_start"`
+	PeriodEnd   string         `json:"period_end"`
+	UnitString  string         `json:"unit_string"`
+	Segments    []SegmentValue `json:"segments"`
+}
+
+// 大株主
+type MajorShareholder struct {
+	Rank    int     `json:"rank"`
+	Name    string  `json:"name"`
+	Address string  `json:"address"`
+	Shares  int     `json:"shares"` // 所有株式数 (株)
+	Ratio   float64 `json:"ratio"`  // 発行済株式 (自己株式を除く) の総数に対する所有株式数の割合 (%)
+}
+
+type ShareholderSummary struct {
+	CompanyName         string             `json:"company_name"`
+	PeriodStart         string             `json:"period_start"`
+	PeriodEnd           string             `json:"period_end"`
+	MajorShareholders   []MajorShareholder `json:"major_shareholders"`
+	TopShareholderRatio float64            `json:"top_shareholder_ratio"` // 大株主の所有割合の合計 (%)
+	IssuedShares        int                `json:"issued_shares"`         // 発行済株式総数 (株)
+	TreasuryShares      int                `json:"treasury_shares"`       // 自己株式数 (株)
+	DividendPerShare    FloatTitleValue    `json:"dividend_per_share"`    // 1株当たり配当額 (円)
+	PayoutRatio         FloatTitleValue    `json:"payout_ratio"`          // 配当性向 (%)
+}
+
+// 役員
+type Officer struct {
+	Name          string `json:"name"`
+	Title         string `json:"title"`          // 役職名
+	DateOfBirth   string `json:"date_of_birth"`  // 生年月日
+	Shares        int    `json:"shares"`         // 所有株式数 (株)
+	IsOutside     bool   `json:"is_outside"`     // 社外取締役・社外監査役
+	IsIndependent bool   `json:"is_independent"` // 独立役員として届け出ているかどうか
+}
+
+type GovernanceSummary struct {
+	CompanyName      string     `json:"company_name"`
+	PeriodStart      string     `json:"period_start"`
+	PeriodEnd        string     `json:"period_end"`
+	Officers         []Officer  `json:"officers"`
+	BoardSize        int        `json:"board_size"`        // 取締役の人数
+	OutsideDirectors int        `json:"outside_directors"` // 社外取締役の人数
+	AuditFirm        string     `json:"audit_firm"`        // 監査法人名
+	AuditFees        TitleValue `json:"audit_fees"`        // 監査証明業務に基づく報酬 (円)
+}
+
+// 投資信託のサマリー
+type FundSummary struct {
+	FundCode         string          `json:"fund_code"`
+	FundName         string          `json:"fund_name"`
+	PeriodStart      string          `json:"period_start"`
+	PeriodEnd        string          `json:"period_end"`
+	NetAssets        TitleValue      `json:"net_assets"`          // 純資産総額 (円)
+	TotalUnits       TitleValue      `json:"total_units"`         // 受益権総口数 (口)
+	NAVPerUnit       FloatTitleValue `json:"nav_per_unit"`        // 1口当たり純資産額 (円)
+	NAVUnitCount     int             `json:"nav_unit_count"`      // 基準価額の口数単位 (例: 10000 の場合は 1万口当たり)
+	TrustFees        TitleValue      `json:"trust_fees"`          // 信託報酬 (円)
+	OperatingExpense TitleValue      `json:"operating_expense"`   // 営業費用合計 (円)
+	MoneyHeldInTrust int             `json:"money_held_in_trust"` // 金銭信託 (円)
+}
+
+// CSV と HTML で値が異なる項目
+type CSVDiffItem struct {
+	Statement string     `json:"statement"` // bs, pl, cf
+	Field     string     `json:"field"`
+	HTML      TitleValue `json:"html"`
+	CSV       TitleValue `json:"csv"`
+	CSVFound  bool       `json:"csv_found"` // CSV に該当する要素があったかどうか
+}
+
+type CSVDiff struct {
+	CompanyName string        `json:"company_name"`
+	PeriodStart string        `json:"period_start"`
+	PeriodEnd   string        `json:"period_end"`
+	DocID       string        `json:"doc_id"`
+	Items       []CSVDiffItem `json:"items"`
 }
 
 type FailedReport struct {
@@ -311,3 +587,40 @@ type NewsResult struct {
 	DateStr  string     `json:"date_str"`
 	AmPm     string     `json:"am_pm"` // "am" か "pm" を設定
 }
+
+// 企業ごとの時系列データ ({EDINETコード}/timeseries.json)
+type TimeSeries struct {
+	EDINETCode  string `json:"edinet_code"`
+	CompanyName string `json:"company_name"`
+	// 項目 (例: pl.sales) ごとの値 (期末日の昇順)
+	Items map[string][]TimeSeriesPoint `json:"items"`
+}
+
+// 時系列データの期間ごとの値
+type TimeSeriesPoint struct {
+	PeriodStart string                 `json:"period_start"`
+	PeriodEnd   string                 `json:"period_end"`
+	Scope       string                 `json:"scope"`
+	Unit        string                 `json:"unit"`        // レポートの単位 (値は円に換算したもの)
+	Value       int                    `json:"value"`       // 最新のレポートの値 (修正がある場合は修正後の値)
+	DocID       string                 `json:"doc_id"`      // 値を取得したレポート
+	IsPrevious  bool                   `json:"is_previous"` // 翌期のレポートの前期の値のみの場合は true
+	Restatement *TimeSeriesRestatement `json:"restatement,omitempty"`
+}
+
+// 翌期のレポートで前期の値が修正された場合の修正前・修正後の値
+type TimeSeriesRestatement struct {
+	OriginalValue int    `json:"original_value"` // 当期として報告された値
+	OriginalDocID string `json:"original_doc_id"`
+	RestatedValue int    `json:"restated_value"` // 翌期のレポートで前期として報告された値
+	RestatedDocID string `json:"restated_doc_id"`
+}
+
+// 時系列データに統合するレポートのサマリー (連結・個別ごと)
+type TimeSeriesSource struct {
+	Summary          Summary
+	PLSummary        PLSummary
+	CFSummary        CFSummary
+	IsPLSummaryValid bool // false の場合は PL の値を統合しない
+	IsCFSummaryValid bool // false の場合は CF の値を統合しない
+}